@@ -0,0 +1,178 @@
+package pinochle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BidRules are the rules of the auction.
+type BidRules struct {
+	Minimum   int // the lowest opening bid
+	Increment int // every bid must raise the last by a multiple of this; 0 allows any raise
+	// StuckDealer makes the dealer take the contract at Minimum when everyone
+	// passes. Otherwise the hand is thrown in.
+	StuckDealer bool
+}
+
+// Contract is what the winner of an auction has undertaken: to score at least
+// Bid in meld and counters with Trump as trump.
+type Contract struct {
+	Bidder int
+	Bid    int
+	Trump  Suit
+}
+
+// Made reports whether meld and counters together make the contract.
+func (contract Contract) Made(meld, counters int) bool {
+	return meld+counters >= contract.Bid
+}
+
+// Score returns what the bidder scores for the hand: meld and counters when the
+// contract is made, and minus the bid when it is set.
+func (contract Contract) Score(meld, counters int) int {
+	if !contract.Made(meld, counters) {
+		return -contract.Bid
+	}
+
+	return meld + counters
+}
+
+// Auction is the bidding for the right to name trump. Bidding goes round the
+// table from the player after the dealer; a player who passes is out, and the
+// last player left takes the contract at their bid.
+type Auction struct {
+	rules  BidRules
+	dealer int
+	turn   int
+	high   int
+	bidder int // -1 until someone bids
+	passed []bool
+}
+
+// NewAuction opens the bidding among players players, of whom dealer dealt.
+func NewAuction(players, dealer int, rules BidRules) *Auction {
+	return &Auction{
+		rules:  rules,
+		dealer: dealer,
+		turn:   (dealer + 1) % players,
+		bidder: -1,
+		passed: make([]bool, players),
+	}
+}
+
+// Turn returns the player who bids next.
+func (a *Auction) Turn() int {
+	return a.turn
+}
+
+// Minimum returns the lowest bid the player whose turn it is may make.
+func (a *Auction) Minimum() int {
+	if a.bidder < 0 {
+		return a.rules.Minimum
+	}
+
+	if a.rules.Increment <= 0 {
+		return a.high + 1
+	}
+
+	return a.high + a.rules.Increment
+}
+
+// HighBid returns the high bidder and their bid, or -1 before anyone has bid.
+func (a *Auction) HighBid() (int, int) {
+	return a.bidder, a.high
+}
+
+// Bid bids amount for player, who must be the player whose turn it is. An
+// opening bid must be at least the minimum, and a raise must go up by a
+// multiple of the increment.
+func (a *Auction) Bid(player, amount int) error {
+	if err := a.check(player); err != nil {
+		return err
+	}
+
+	if amount < a.Minimum() {
+		return fmt.Errorf("a bid of %v is too low: the least is %v", amount, a.Minimum())
+	}
+
+	if a.bidder >= 0 && a.rules.Increment > 0 && (amount-a.high)%a.rules.Increment != 0 {
+		return fmt.Errorf("a bid of %v does not raise %v by a multiple of %v", amount, a.high, a.rules.Increment)
+	}
+
+	a.high = amount
+	a.bidder = player
+	a.advance()
+	return nil
+}
+
+// Pass drops player, who must be the player whose turn it is, from the auction.
+func (a *Auction) Pass(player int) error {
+	if err := a.check(player); err != nil {
+		return err
+	}
+
+	a.passed[player] = true
+	a.advance()
+	return nil
+}
+
+func (a *Auction) check(player int) error {
+	if a.Done() {
+		return errors.New("the auction is over")
+	}
+
+	if player != a.turn {
+		return fmt.Errorf("it is player %v's turn to bid, not player %v's", a.turn, player)
+	}
+
+	return nil
+}
+
+// advance moves the turn to the next player still bidding.
+func (a *Auction) advance() {
+	if a.Done() {
+		return
+	}
+
+	for next := (a.turn + 1) % len(a.passed); ; next = (next + 1) % len(a.passed) {
+		if !a.passed[next] {
+			a.turn = next
+			return
+		}
+	}
+}
+
+// remaining returns how many players have not passed.
+func (a *Auction) remaining() int {
+	count := 0
+	for _, passed := range a.passed {
+		if !passed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Done reports whether the bidding is over: everyone has passed, or everyone
+// but the high bidder has.
+func (a *Auction) Done() bool {
+	remaining := a.remaining()
+	return remaining == 0 || (remaining == 1 && a.bidder >= 0 && !a.passed[a.bidder])
+}
+
+// Contract returns the contract the auction was won at, once it is done. Trump
+// is left for the bidder to name. It returns false if the auction is still open,
+// or if everyone passed and the dealer isn't stuck, so the hand is thrown in.
+func (a *Auction) Contract() (Contract, bool) {
+	switch {
+	case !a.Done():
+		return Contract{}, false
+	case a.bidder >= 0:
+		return Contract{Bidder: a.bidder, Bid: a.high}, true
+	case a.rules.StuckDealer:
+		return Contract{Bidder: a.dealer, Bid: a.rules.Minimum}, true
+	}
+
+	return Contract{}, false
+}
@@ -0,0 +1,651 @@
+package pinochle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// AuctionMatch is a match of auction pinochle for three or more players, alone
+// or in partnerships. Every hand is bid for: the high bidder names trump and
+// improves their hand, by taking the widow and burying as many cards, or by
+// trading cards with their partner. Their side then has to make the bid in meld
+// and counters, or is set back by it. Players are numbered from 0 in order round
+// the table, and scores are kept by side.
+type AuctionMatch struct {
+	rules       Rules
+	pointValues points
+	seats       []*seat
+	sides       []int // the side of each player
+	scores      []int // by side
+	deck        deckSpec
+	widowSize   int
+	passSize    int
+	dealer      int
+	shuffler    Shuffler
+	seeds       *rand.Rand
+
+	phase    auctionPhase
+	auction  *Auction
+	contract Contract
+	widow    []Card
+	buried   []Card
+	passed   int // how many passes of cards have been made
+
+	turn      int
+	trick     *Trick
+	lastTrick *Trick
+	played    []Card
+	taken     []int
+
+	history []AuctionHandScore
+	over    bool
+	winner  int
+}
+
+// auctionPhase is the stage an AuctionMatch's hand has reached.
+type auctionPhase int
+
+const (
+	phaseBidding auctionPhase = iota
+	phaseTrump
+	phaseBury
+	phasePass
+	phasePlay
+	phaseDone
+)
+
+// AuctionHandScore is the outcome of one hand of an AuctionMatch. Melds and
+// Counters are indexed by side; the bidder's Counters include the buried
+// cards, and the melds of a side that took no trick are lost, the bidder's
+// included, so that the contract must then be made on counters. A hand thrown in
+// because everyone passed has no Contract and scores nothing.
+type AuctionHandScore struct {
+	Contract Contract
+	Made     bool
+	Conceded bool
+	ThrownIn bool
+	Melds    []int
+	Counters []int
+}
+
+// InitializeCutthroat builds a three-handed AuctionMatch, with fifteen cards
+// each and a widow of three.
+func InitializeCutthroat(pOne, pTwo, pThree Player, rules Rules) AuctionMatch {
+	return newAuctionMatch([]Player{pOne, pTwo, pThree}, []int{0, 1, 2}, singleDeck, 3, 0, rules)
+}
+
+// InitializePartnership builds a four-handed AuctionMatch, with twelve cards
+// each and no widow. Players 0 and 2 are partners against players 1 and 3; once
+// trump is named the bidder's partner passes the bidder four cards, and the
+// bidder passes four back.
+func InitializePartnership(pOne, pTwo, pThree, pFour Player, rules Rules) AuctionMatch {
+	return newAuctionMatch([]Player{pOne, pTwo, pThree, pFour}, []int{0, 1, 0, 1}, singleDeck, 0, 4, rules)
+}
+
+// InitializeDoubleDeck builds a four-handed AuctionMatch played in partnerships
+// like InitializePartnership, but with the 80-card double deck: four of every
+// ace, ten, king, queen and jack, and twenty cards each. Play it under
+// DoubleDeckRules, or other Rules that value the triple and quadruple melds.
+func InitializeDoubleDeck(pOne, pTwo, pThree, pFour Player, rules Rules) AuctionMatch {
+	return newAuctionMatch([]Player{pOne, pTwo, pThree, pFour}, []int{0, 1, 0, 1}, doubleDeck, 0, 4, rules)
+}
+
+func newAuctionMatch(players []Player, sides []int, deck deckSpec, widowSize, passSize int, rules Rules) AuctionMatch {
+	match := AuctionMatch{
+		rules:       rules,
+		pointValues: rules.points(),
+		sides:       sides,
+		deck:        deck,
+		widowSize:   widowSize,
+		passSize:    passSize,
+		dealer:      len(players) - 1,
+		phase:       phaseDone,
+	}
+
+	for _, player := range players {
+		match.seats = append(match.seats, &seat{player: player})
+		if sides[len(match.seats)-1] >= len(match.scores) {
+			match.scores = append(match.scores, 0)
+		}
+	}
+
+	return match
+}
+
+// SetShuffler makes every following shuffled NewGame use shuffler.
+func (match *AuctionMatch) SetShuffler(shuffler Shuffler) {
+	match.shuffler = shuffler
+}
+
+// SetSeed makes every following shuffled NewGame derive its deal from seed, so
+// that a whole match can be replayed.
+func (match *AuctionMatch) SetSeed(seed int64) {
+	match.seeds = rand.New(rand.NewSource(seed))
+}
+
+// NewGame passes the deal to the next player, deals a new hand and opens the
+// auction. If shuffle is false the deck is left in order.
+func (match *AuctionMatch) NewGame(shuffle bool) error {
+	if match.over {
+		return errors.New("the match is over")
+	}
+
+	var shuffler Shuffler
+	if shuffle {
+		shuffler = match.shuffler
+		if shuffler == nil {
+			if match.seeds == nil {
+				match.seeds = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+			}
+
+			shuffler = NewSeededShuffler(match.seeds.Int63())
+		}
+	}
+
+	match.dealer = (match.dealer + 1) % len(match.seats)
+	for _, s := range match.seats {
+		s.hand = nil
+		s.melds = nil
+		s.meldItems = nil
+		s.currentMeldScore, s.currentTrickScore = 0, 0
+	}
+
+	deck := match.deck.build(shuffler)
+	match.widow = nil
+	for i := 0; i < match.widowSize; i++ {
+		card, _ := deck.pop()
+		match.widow = append(match.widow, card)
+	}
+
+	// Cards go round in packets of three, or of four when the hands don't
+	// divide into threes.
+	packet := 3
+	if (len(deck.stack)/len(match.seats))%packet != 0 {
+		packet = 4
+	}
+
+	for player := (match.dealer + 1) % len(match.seats); len(deck.stack) > 0; player = (player + 1) % len(match.seats) {
+		for i := 0; i < packet && len(deck.stack) > 0; i++ {
+			card, _ := deck.pop()
+			match.seats[player].pushToHand(card)
+		}
+	}
+
+	match.phase = phaseBidding
+	match.auction = NewAuction(len(match.seats), match.dealer, match.rules.Bidding)
+	match.contract = Contract{Bidder: -1}
+	match.buried = nil
+	match.passed = 0
+	match.trick, match.lastTrick, match.played = nil, nil, nil
+	match.taken = make([]int, len(match.seats))
+	match.turn = match.auction.Turn()
+	return nil
+}
+
+// Players returns the number of players in the match.
+func (match *AuctionMatch) Players() int {
+	return len(match.seats)
+}
+
+// Side returns the side player scores for. In cutthroat every player is their
+// own side.
+func (match *AuctionMatch) Side(player int) int {
+	return match.sides[player]
+}
+
+// Partner returns player's partner, or player if they play alone.
+func (match *AuctionMatch) Partner(player int) int {
+	for i := range match.seats {
+		other := (player + i + 1) % len(match.seats)
+		if match.sides[other] == match.sides[player] {
+			return other
+		}
+	}
+
+	return player
+}
+
+// Dealer returns the player who dealt the current hand.
+func (match *AuctionMatch) Dealer() int {
+	return match.dealer
+}
+
+// Hand returns the cards in player's hand.
+func (match *AuctionMatch) Hand(player int) []Card {
+	return match.seats[player].getHand()
+}
+
+// Widow returns the widow. It stays hidden, and nil, until the auction is over.
+func (match *AuctionMatch) Widow() []Card {
+	if match.phase == phaseBidding {
+		return nil
+	}
+
+	return match.widow
+}
+
+// Turn returns the player who has to act next: to bid, to name trump, to bury
+// or pass cards, or to play.
+func (match *AuctionMatch) Turn() int {
+	return match.turn
+}
+
+// Bidding reports whether the auction for the current hand is still open.
+func (match *AuctionMatch) Bidding() bool {
+	return match.phase == phaseBidding
+}
+
+// Playing reports whether the current hand is being played out.
+func (match *AuctionMatch) Playing() bool {
+	return match.phase == phasePlay
+}
+
+// MinimumBid returns the least bid the player whose turn it is may make.
+func (match *AuctionMatch) MinimumBid() int {
+	return match.auction.Minimum()
+}
+
+// Bid bids amount for player, who must be the player whose turn it is.
+func (match *AuctionMatch) Bid(player, amount int) error {
+	if match.phase != phaseBidding {
+		return errors.New("the auction is not open")
+	}
+
+	if err := match.auction.Bid(player, amount); err != nil {
+		return err
+	}
+
+	match.afterBid()
+	return nil
+}
+
+// Pass drops player, who must be the player whose turn it is, from the auction.
+func (match *AuctionMatch) Pass(player int) error {
+	if match.phase != phaseBidding {
+		return errors.New("the auction is not open")
+	}
+
+	if err := match.auction.Pass(player); err != nil {
+		return err
+	}
+
+	match.afterBid()
+	return nil
+}
+
+// afterBid moves the turn on, or hands the widow to the winner of a closed
+// auction. If no one took the contract the hand is thrown in.
+func (match *AuctionMatch) afterBid() {
+	match.turn = match.auction.Turn()
+	if !match.auction.Done() {
+		return
+	}
+
+	contract, ok := match.auction.Contract()
+	if !ok {
+		match.history = append(match.history, AuctionHandScore{
+			Contract: match.contract,
+			ThrownIn: true,
+			Melds:    make([]int, len(match.scores)),
+			Counters: make([]int, len(match.scores)),
+		})
+		match.phase = phaseDone
+		return
+	}
+
+	match.contract = contract
+	for _, card := range match.widow {
+		match.seats[match.contract.Bidder].pushToHand(card)
+	}
+
+	match.phase = phaseTrump
+	match.turn = match.contract.Bidder
+}
+
+// Contract returns the contract of the current hand; Trump is empty until the
+// bidder names it. Bidder is -1 while the auction is open, or when the hand was
+// thrown in.
+func (match *AuctionMatch) Contract() Contract {
+	return match.contract
+}
+
+// HighBid returns the high bidder and their bid so far, or -1 before anyone has
+// bid.
+func (match *AuctionMatch) HighBid() (int, int) {
+	return match.auction.HighBid()
+}
+
+// NameTrump makes suit trump for the hand. Only the bidder may name trump, once
+// the auction is over.
+func (match *AuctionMatch) NameTrump(suit Suit) error {
+	if match.phase != phaseTrump {
+		return errors.New("trump can only be named by the bidder after the auction")
+	}
+
+	for _, s := range suits {
+		if Suit(s) == suit {
+			match.contract.Trump = suit
+			switch {
+			case match.widowSize > 0:
+				match.phase = phaseBury
+			case match.passSize > 0:
+				match.phase = phasePass
+				match.turn = match.Partner(match.contract.Bidder)
+			default:
+				match.startPlay()
+			}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%q is not a suit", suit)
+}
+
+// Bury discards cards from the bidder's hand, as many as the widow held. The
+// counters among them score for the bidder. Every player then melds the best
+// they can, and the bidder leads the first trick.
+func (match *AuctionMatch) Bury(cards []Card) error {
+	if match.phase != phaseBury {
+		return errors.New("cards can only be buried by the bidder once trump is named")
+	}
+
+	if len(cards) != match.widowSize {
+		return fmt.Errorf("%v cards must be buried, not %v", match.widowSize, len(cards))
+	}
+
+	bidder := match.seats[match.contract.Bidder]
+	if !containsCards(bidder.getHand(), cards) {
+		return fmt.Errorf("the bidder's hand does not hold %v", cards)
+	}
+
+	for _, card := range cards {
+		bidder.play(card)
+		bidder.scoreTrickPoints(match.pointValues.cardPoints(card))
+	}
+
+	match.buried = append([]Card(nil), cards...)
+	match.startPlay()
+	return nil
+}
+
+// PassCards passes cards from player to their partner. Once trump is named the
+// bidder's partner passes first, and then the bidder passes as many back.
+func (match *AuctionMatch) PassCards(player int, cards []Card) error {
+	if match.phase != phasePass {
+		return errors.New("cards can only be passed once trump is named")
+	}
+
+	if player != match.turn {
+		return fmt.Errorf("it is player %v's turn to pass, not player %v's", match.turn, player)
+	}
+
+	if len(cards) != match.passSize {
+		return fmt.Errorf("%v cards must be passed, not %v", match.passSize, len(cards))
+	}
+
+	from, to := match.seats[player], match.seats[match.Partner(player)]
+	if !containsCards(from.getHand(), cards) {
+		return fmt.Errorf("player %v's hand does not hold %v", player, cards)
+	}
+
+	for _, card := range cards {
+		from.play(card)
+		to.pushToHand(card)
+	}
+
+	match.passed++
+	match.turn = match.Partner(player)
+	if match.passed == 2 {
+		match.startPlay()
+	}
+
+	return nil
+}
+
+// startPlay melds the best of every hand and has the bidder lead.
+func (match *AuctionMatch) startPlay() {
+	for _, s := range match.seats {
+		score := bestMelds(s.getHand(), nil, string(match.contract.Trump), match.pointValues)
+		s.meldItems = score.Items
+		for _, item := range score.Items {
+			s.storeMeld(item.Cards)
+		}
+
+		s.scoreMeldPoints(score.Total)
+	}
+
+	match.phase = phasePlay
+	match.trick = newTrick(match.contract.Bidder, len(match.seats), string(match.contract.Trump), match.pointValues)
+	match.turn = match.contract.Bidder
+}
+
+// Concede throws the hand in before the bidder leads to the first trick. The
+// bidder's side loses the bid, and no one else scores.
+func (match *AuctionMatch) Concede() error {
+	started := match.phase == phasePlay && len(match.played) > 0
+	if match.phase == phaseBidding || match.phase == phaseDone || started {
+		return errors.New("the bidder can only concede between the auction and the first trick")
+	}
+
+	hand := AuctionHandScore{
+		Contract: match.contract,
+		Conceded: true,
+		Melds:    make([]int, len(match.scores)),
+		Counters: make([]int, len(match.scores)),
+	}
+
+	match.scores[match.sides[match.contract.Bidder]] -= match.contract.Bid
+	match.finishHand(hand)
+	return nil
+}
+
+// Melds returns player's meld for the hand, once the bidder has buried.
+func (match *AuctionMatch) Melds(player int) MeldScore {
+	s := match.seats[player]
+	return MeldScore{Items: s.meldItems, Total: s.meldScore()}
+}
+
+// LegalPlays returns the cards player may play to the current trick.
+func (match *AuctionMatch) LegalPlays(player int) []Card {
+	if match.phase != phasePlay || player != match.turn {
+		return nil
+	}
+
+	return match.trick.Legal(match.seats[player].getHand())
+}
+
+// Trick returns the trick being played, or nil when no hand is.
+func (match *AuctionMatch) Trick() *Trick {
+	return match.trick
+}
+
+// LastTrick returns the last completed trick, or nil before the first.
+func (match *AuctionMatch) LastTrick() *Trick {
+	return match.lastTrick
+}
+
+// Play plays card for player, who must be the player whose turn it is, and
+// who must follow the rules of the trick. Passing DummyCard lets player's
+// Player choose the card. When everyone has played the trick goes to its
+// winner, who leads the next; after the last trick the hand is scored.
+func (match *AuctionMatch) Play(player int, card Card) error {
+	if match.phase != phasePlay {
+		return errors.New("no hand is being played")
+	}
+
+	if player != match.turn {
+		return fmt.Errorf("it is player %v's turn to play, not player %v's", match.turn, player)
+	}
+
+	p := match.seats[player]
+	if CompareCards(card, DummyCard) {
+		chosen, err := p.player.Play(match.table(player))
+		if err != nil {
+			return err
+		}
+
+		card = chosen
+	}
+
+	if ok, _ := p.handContains(card); !ok {
+		return fmt.Errorf("player %v's hand does not contain %v", player, card)
+	}
+
+	if err := trickViolation(p.getHand(), match.trick.plays, card, string(match.contract.Trump)); err != nil {
+		return err
+	}
+
+	p.play(card)
+	match.trick.Play(card)
+	match.played = append(match.played, card)
+	match.turn = match.trick.Next()
+	if match.trick.Complete() {
+		match.takeTrick()
+	}
+
+	return nil
+}
+
+// takeTrick gives the completed trick to its winner.
+func (match *AuctionMatch) takeTrick() {
+	winner, points := match.trick.Resolve()
+	last := !match.seats[winner].hasCards()
+	if last {
+		points += match.pointValues.lastTrick
+	}
+
+	match.seats[winner].scoreTrickPoints(points)
+	match.taken[winner]++
+	match.lastTrick = match.trick
+	match.trick = newTrick(winner, len(match.seats), string(match.contract.Trump), match.pointValues)
+	match.turn = winner
+	if last {
+		match.trick = nil
+		match.scoreHand()
+	}
+}
+
+// scoreHand scores a hand played to the end. The bidder's side scores its meld
+// and counters if together they make the bid, and is set back by the bid
+// otherwise. Every other side scores its counters, and its meld if it took a
+// trick.
+func (match *AuctionMatch) scoreHand() {
+	hand := AuctionHandScore{
+		Contract: match.contract,
+		Melds:    make([]int, len(match.scores)),
+		Counters: make([]int, len(match.scores)),
+	}
+
+	melds := make([]int, len(match.scores))
+	tookTrick := make([]bool, len(match.scores))
+	for i, s := range match.seats {
+		hand.Counters[match.sides[i]] += s.currentTrickScore
+		melds[match.sides[i]] += s.currentMeldScore
+		tookTrick[match.sides[i]] = tookTrick[match.sides[i]] || match.taken[i] > 0
+	}
+
+	for side := range match.scores {
+		if tookTrick[side] {
+			hand.Melds[side] = melds[side]
+		}
+	}
+
+	bidding := match.sides[match.contract.Bidder]
+
+	hand.Made = match.contract.Made(hand.Melds[bidding], hand.Counters[bidding])
+	for side := range match.scores {
+		if side == bidding {
+			match.scores[side] += match.contract.Score(hand.Melds[side], hand.Counters[side])
+			continue
+		}
+
+		match.scores[side] += hand.Melds[side] + hand.Counters[side]
+	}
+
+	match.finishHand(hand)
+}
+
+// finishHand records hand and decides the match if any side has reached
+// PlayingTo. The bidder's side wins first if it has; otherwise the highest
+// score wins, and a tie for it plays on.
+func (match *AuctionMatch) finishHand(hand AuctionHandScore) {
+	match.history = append(match.history, hand)
+	match.phase = phaseDone
+	for _, s := range match.seats {
+		s.currentMeldScore, s.currentTrickScore = 0, 0
+	}
+
+	bidding := match.sides[match.contract.Bidder]
+	if match.scores[bidding] >= match.rules.PlayingTo {
+		match.over, match.winner = true, bidding
+		return
+	}
+
+	best, tied := -1, false
+	for side, score := range match.scores {
+		switch {
+		case score < match.rules.PlayingTo:
+		case best < 0 || score > match.scores[best]:
+			best, tied = side, false
+		case score == match.scores[best]:
+			tied = true
+		}
+	}
+
+	if best >= 0 && !tied {
+		match.over, match.winner = true, best
+	}
+}
+
+// Scores returns the score of every side.
+func (match *AuctionMatch) Scores() []int {
+	return append([]int(nil), match.scores...)
+}
+
+// ScoreHistory returns the outcome of every finished hand, in order.
+func (match *AuctionMatch) ScoreHistory() []AuctionHandScore {
+	return match.history
+}
+
+// MatchOver reports whether a side has won the match.
+func (match *AuctionMatch) MatchOver() bool {
+	return match.over
+}
+
+// Winner returns the side that won the match, or -1 while it goes on.
+func (match *AuctionMatch) Winner() int {
+	if !match.over {
+		return -1
+	}
+
+	return match.winner
+}
+
+// table returns what player can see. Trump carries only the trump suit, as no
+// card is turned up.
+func (match *AuctionMatch) table(player int) Table {
+	p := match.seats[player]
+	led := DummyCard
+	var trick []Card
+	if match.trick != nil && len(match.trick.plays) > 0 {
+		led = match.trick.plays[0]
+		trick = match.trick.Plays()
+	}
+
+	return Table{
+		Hand:    append([]Card(nil), p.getHand()...),
+		Legal:   match.LegalPlays(player),
+		Led:     led,
+		Trump:   Card{"", string(match.contract.Trump)},
+		Melds:   p.getMelds(),
+		Seen:    append([]Card(nil), match.played...),
+		Trick:   trick,
+		Players: len(match.seats),
+
+		pointValues: match.pointValues,
+		deck:        match.deck,
+	}
+}
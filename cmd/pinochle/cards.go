@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ClaytonMcCray/pinochle"
+)
+
+// suitOrder alternates the colours, so that neighbouring suits are easy to tell
+// apart.
+var suitOrder = []pinochle.Suit{pinochle.Spades, pinochle.Hearts, pinochle.Clubs, pinochle.Diamonds}
+
+var rankOrder = []pinochle.Rank{pinochle.Ace, pinochle.Ten, pinochle.King, pinochle.Queen, pinochle.Jack, pinochle.Nine}
+
+// suitIndex and rankIndex place a card in suitOrder and rankOrder.
+func suitIndex(card pinochle.Card) int {
+	for i, suit := range suitOrder {
+		if card.Suit() == suit {
+			return i
+		}
+	}
+
+	return len(suitOrder)
+}
+
+func rankIndex(card pinochle.Card) int {
+	for i, rank := range rankOrder {
+		if card.Rank() == rank {
+			return i
+		}
+	}
+
+	return len(rankOrder)
+}
+
+// sortHand returns a copy of hand sorted by suit, and within a suit from the
+// ace down.
+func sortHand(hand []pinochle.Card) []pinochle.Card {
+	sorted := append([]pinochle.Card(nil), hand...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if suitIndex(sorted[i]) != suitIndex(sorted[j]) {
+			return suitIndex(sorted[i]) < suitIndex(sorted[j])
+		}
+
+		return rankIndex(sorted[i]) < rankIndex(sorted[j])
+	})
+
+	return sorted
+}
+
+// formatCards writes cards with their suit symbols, separated by spaces.
+func formatCards(cards []pinochle.Card) string {
+	symbols := make([]string, len(cards))
+	for i, card := range cards {
+		symbols[i] = card.Symbol()
+	}
+
+	return strings.Join(symbols, " ")
+}
+
+// formatHand writes hand sorted, with a gap between the suits.
+func formatHand(hand []pinochle.Card) string {
+	var groups []string
+	var group []pinochle.Card
+	for i, card := range sortHand(hand) {
+		if i > 0 && card.Suit() != group[0].Suit() {
+			groups = append(groups, formatCards(group))
+			group = nil
+		}
+
+		group = append(group, card)
+	}
+
+	if len(group) > 0 {
+		groups = append(groups, formatCards(group))
+	}
+
+	return strings.Join(groups, "   ")
+}
+
+// formatMelds writes each meld in brackets.
+func formatMelds(melds [][]pinochle.Card) string {
+	groups := make([]string, len(melds))
+	for i, meld := range melds {
+		groups[i] = "[" + formatCards(meld) + "]"
+	}
+
+	return strings.Join(groups, " ")
+}
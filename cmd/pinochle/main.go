@@ -0,0 +1,345 @@
+// Command pinochle plays a two-handed pinochle match against the Computer in
+// the terminal.
+//
+// By default it prompts line by line: cards are typed as their rank and suit,
+// such as 10H, QS or A♦, and at any prompt "out" declares out and "quit" leaves
+// the match. With -tui it takes over the terminal instead, and cards are picked
+// with the arrow keys. With -record it writes the game record of the match to
+// a file as it is played.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ClaytonMcCray/pinochle"
+)
+
+// errQuit is returned when the player leaves the match.
+var errQuit = errors.New("quit")
+
+func main() {
+	seed := flag.Int64("seed", 0, "seed the deals, so that a match can be replayed; 0 picks one from the clock")
+	playingTo := flag.Int("to", pinochle.ClassicRules.PlayingTo, "score that wins the match")
+	level := flag.String("level", "easy", "strength of the Computer: easy, medium or hard")
+	fullScreen := flag.Bool("tui", false, "play in a full-screen terminal UI")
+	delay := flag.Duration("delay", 600*time.Millisecond, "how long the TUI shows each finished trick")
+	recordPath := flag.String("record", "", "write the game record of the match to this file")
+	flag.Parse()
+
+	strengths := map[string]pinochle.Strength{"easy": pinochle.Easy, "medium": pinochle.Medium, "hard": pinochle.Hard}
+	strength, ok := strengths[strings.ToLower(*level)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown level %q\n", *level)
+		os.Exit(2)
+	}
+
+	rules := pinochle.ClassicRules
+	rules.PlayingTo = *playingTo
+	computer := pinochle.NewComputer(strength)
+	computer.Seed = *seed
+	match := pinochle.InitializeMatch(&pinochle.Human{}, computer, rules)
+	if *seed != 0 {
+		match.SetSeed(*seed)
+	}
+
+	var file *os.File
+	var record *pinochle.RecordWriter
+	if *recordPath != "" {
+		var err error
+		if file, err = os.Create(*recordPath); err != nil {
+			fmt.Fprintln(os.Stderr, "pinochle:", err)
+			os.Exit(1)
+		}
+
+		record = pinochle.NewRecordWriter(file, &match, map[string]string{
+			"Event":     "Pinochle against the Computer",
+			"PlayerOne": "You",
+			"PlayerTwo": "Computer (" + strings.ToLower(*level) + ")",
+		})
+		match.Subscribe(record)
+	}
+
+	s := &session{match: &match, computer: computer}
+	var err error
+	if *fullScreen {
+		err = runScreen(s, *delay)
+	} else {
+		s.ui = &promptUI{match: &match, in: bufio.NewScanner(os.Stdin), out: os.Stdout}
+		err = s.run()
+	}
+
+	status := 0
+	if err != nil && err != errQuit && err != io.EOF {
+		fmt.Fprintln(os.Stderr, "pinochle:", err)
+		status = 1
+	}
+
+	if record != nil {
+		if err := closeRecord(record, file); err != nil {
+			fmt.Fprintf(os.Stderr, "pinochle: writing %v: %v\n", *recordPath, err)
+			status = 1
+		}
+	}
+
+	os.Exit(status)
+}
+
+// closeRecord closes the file a RecordWriter wrote to, and returns the first
+// error the writing or closing met.
+func closeRecord(record *pinochle.RecordWriter, file io.Closer) error {
+	err := file.Close()
+	if record.Err() != nil {
+		return record.Err()
+	}
+
+	return err
+}
+
+// ui is how a session deals with the person at the terminal, who is playerOne.
+type ui interface {
+	// play has Match accept the card playerOne plays to the trick.
+	play() error
+	// meld offers playerOne the meld it has won the chance to make.
+	meld() error
+	// logf reports what happened.
+	logf(format string, args ...interface{})
+	// trickDone shows the trick just decided, before it is cleared away.
+	trickDone()
+}
+
+// session plays a Match between the person at the terminal, as playerOne, and
+// computer, as playerTwo. Everything goes through the Match API; ui only asks
+// for decisions and reports what happened.
+type session struct {
+	match    *pinochle.Match
+	computer *pinochle.Computer
+	ui       ui
+}
+
+// run plays games until the match is over.
+func (s *session) run() error {
+	for game := 1; !s.match.MatchOver(); game++ {
+		if err := s.match.NewGame(true); err != nil {
+			return err
+		}
+
+		s.ui.logf("=== Game %v (seed %v) ===", game, s.match.Seed())
+		if err := s.playGame(); err != nil {
+			return err
+		}
+
+		s.logGame()
+	}
+
+	s.logOutcome()
+	return nil
+}
+
+// playGame plays the trick phase, with its melds and draws, and then the
+// playoff. It stops early if the match ends by declaring out.
+func (s *session) playGame() error {
+	for trickPhase, lastCard := s.match.TrickPhase(); trickPhase; trickPhase, lastCard = s.match.TrickPhase() {
+		if err := s.playTrick(); err != nil || s.match.MatchOver() {
+			return err
+		}
+
+		if err := s.meld(); err != nil || s.match.MatchOver() {
+			return err
+		}
+
+		if err := s.draw(lastCard); err != nil {
+			return err
+		}
+	}
+
+	s.ui.logf("The stock is gone: follow suit and head the trick from here on.")
+	for s.match.Playoff() {
+		if err := s.playTrick(); err != nil || s.match.MatchOver() {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// playTrick has each player play a card in turn, then decides the trick and
+// scores it.
+func (s *session) playTrick() error {
+	for i := 0; i < 2; i++ {
+		if !s.match.PlayerOneTurn() {
+			if err := s.match.PlayerTwoPlayed(pinochle.DummyCard); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := s.ui.play(); err != nil || s.match.MatchOver() {
+			return err
+		}
+	}
+
+	if err := s.match.DecideTrickWinner(); err != nil {
+		return err
+	}
+
+	if err := s.match.AssignTrickPoints(); err != nil {
+		return err
+	}
+
+	trick := s.match.LastTrick()
+	winner, points := trick.Resolve()
+	var plays []string
+	for i, card := range trick.Plays() {
+		plays = append(plays, fmt.Sprintf("%v (%v)", card.Symbol(), name(trick.Seat(i))))
+	}
+
+	taker := "The Computer takes"
+	if winner == 0 {
+		taker = "You take"
+	}
+
+	s.ui.logf("Trick %v. %v %v.", strings.Join(plays, ", "), taker, plural(points, "point"))
+	s.ui.trickDone()
+	return nil
+}
+
+// meld gives the winner of the trick its chance to meld.
+func (s *session) meld() error {
+	if !s.match.PlayerOneWonTrick() {
+		s.computerMeld()
+		return nil
+	}
+
+	defer s.match.DoneMelding()
+	if len(s.match.PlayerOneMeldableCards()) == 0 {
+		return nil
+	}
+
+	melded := len(s.match.PlayerOneMeldItems())
+	if err := s.ui.meld(); err != nil {
+		return err
+	}
+
+	if items := s.match.PlayerOneMeldItems(); len(items) > melded {
+		item := items[len(items)-1]
+		s.ui.logf("You meld %v for %v.", item.Name, plural(item.Points, "point"))
+	}
+
+	return nil
+}
+
+// computerMeld has the Computer make the meld it chooses, exchanging the dix
+// when it can.
+func (s *session) computerMeld() {
+	defer s.match.DoneMelding()
+	table := s.match.PlayerTwoTable()
+	meld, err := s.computer.Meld(table)
+	if err != nil || len(meld) == 0 {
+		return
+	}
+
+	dix := len(meld) == 1 && meld[0].Rank() == pinochle.Nine && meld[0].Suit() == table.Trump.Suit()
+	if dix && s.match.PlayerTwoExchangeDix() == nil {
+		s.ui.logf("The Computer exchanges the dix for %v.", table.Trump.Symbol())
+		return
+	}
+
+	if s.match.PlayerTwoMeld(meld) {
+		items := s.match.PlayerTwoMeldItems()
+		item := items[len(items)-1]
+		s.ui.logf("The Computer melds %v (%v) for %v.", item.Name, formatCards(item.Cards), plural(item.Points, "point"))
+	}
+}
+
+// draw has the winner of the trick draw first. On the last card of the stock
+// the loser takes the trump card.
+func (s *session) draw(lastCard bool) error {
+	first, second := s.match.DealToPlayerTwo, s.match.DealToPlayerOne
+	if lastCard {
+		second = s.match.DealTrumpToPlayerOne
+	}
+
+	if s.match.PlayerOneWonTrick() {
+		first, second = s.match.DealToPlayerOne, s.match.DealToPlayerTwo
+		if lastCard {
+			second = s.match.DealTrumpToPlayerTwo
+		}
+	}
+
+	if err := first(); err != nil {
+		return err
+	}
+
+	if err := second(); err != nil {
+		return err
+	}
+
+	hand := s.match.PlayerOneHand()
+	s.ui.logf("You draw %v.", hand[len(hand)-1].Symbol())
+	return nil
+}
+
+// logGame reports the score of the game just finished.
+func (s *session) logGame() {
+	history := s.match.ScoreHistory()
+	if len(history) == 0 {
+		return
+	}
+
+	last := history[len(history)-1]
+	s.ui.logf("You melded %v and took %v; the Computer melded %v and took %v.",
+		last.PlayerOneMeld, last.PlayerOneTricks, last.PlayerTwoMeld, last.PlayerTwoTricks)
+	s.ui.logf("%v", scores(s.match))
+}
+
+// logOutcome announces the winner of the match.
+func (s *session) logOutcome() {
+	outcome := s.match.Outcome()
+	switch {
+	case outcome.DeclaredOut && outcome.WrongDeclaration:
+		s.ui.logf("The declaration was wrong, and loses the match.")
+	case outcome.DeclaredOut:
+		s.ui.logf("The declaration was right, and wins the match.")
+	}
+
+	if outcome.PlayerOneWon {
+		s.ui.logf("You win the match!")
+	} else {
+		s.ui.logf("The Computer wins the match.")
+	}
+}
+
+// scores writes the match scores, with the game in progress.
+func scores(match *pinochle.Match) string {
+	game := match.GameScore()
+	return fmt.Sprintf("You %v (+%v)   Computer %v (+%v)   playing to %v",
+		match.PlayerOneScore(), game.PlayerOneMeld+game.PlayerOneTricks,
+		match.PlayerTwoScore(), game.PlayerTwoMeld+game.PlayerTwoTricks,
+		match.PlayingTo())
+}
+
+// name returns who sits at seat of a Trick: playerOne, at seat 0, is the person
+// at the terminal.
+func name(seat int) string {
+	if seat == 0 {
+		return "you"
+	}
+
+	return "Computer"
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%v %v", n, unit)
+	}
+
+	return fmt.Sprintf("%v %vs", n, unit)
+}
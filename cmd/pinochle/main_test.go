@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ClaytonMcCray/pinochle"
+)
+
+func cards(t *testing.T, text string) []pinochle.Card {
+	t.Helper()
+	parsed, err := pinochle.ParseCards(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func count(hand []pinochle.Card, card pinochle.Card) int {
+	n := 0
+	for _, c := range hand {
+		if pinochle.CompareCards(c, card) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// promptMatch deals a seeded game against the Computer, and has it lead if it
+// is first, so that playerOne is left to play.
+func promptMatch(t *testing.T, seed int64) *pinochle.Match {
+	t.Helper()
+	match := pinochle.InitializeMatch(&pinochle.Human{}, pinochle.NewComputer(pinochle.Easy), pinochle.ClassicRules)
+	if err := match.NewGameWithSeed(seed); err != nil {
+		t.Fatal(err)
+	}
+
+	if !match.PlayerOneTurn() {
+		if err := match.PlayerTwoPlayed(pinochle.DummyCard); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &match
+}
+
+func newPromptUI(match *pinochle.Match, input string) (*promptUI, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &promptUI{match: match, in: bufio.NewScanner(strings.NewReader(input)), out: &out}, &out
+}
+
+func TestSortHand(t *testing.T) {
+	hand := cards(t, "9D JS AH 10C KS 9S AS QH AD 10H")
+	want := cards(t, "AS KS JS 9S AH 10H QH 10C AD 9D")
+	sorted := sortHand(hand)
+	if formatCards(sorted) != formatCards(want) {
+		t.Errorf("sorted %v, want %v", formatCards(sorted), formatCards(want))
+	}
+
+	if formatCards(hand) != "9♦ J♠ A♥ 10♣ K♠ 9♠ A♠ Q♥ A♦ 10♥" {
+		t.Errorf("sortHand changed the hand it was given: %v", formatCards(hand))
+	}
+
+	if len(sortHand(nil)) != 0 {
+		t.Error("an empty hand sorted to cards")
+	}
+}
+
+func TestFormatHand(t *testing.T) {
+	tests := []struct {
+		hand string
+		want string
+	}{
+		{"", ""},
+		{"QS", "Q♠"},
+		{"9D JS AH 10C KS", "K♠ J♠   A♥   10♣   9♦"},
+		{"QH 9H 10H", "10♥ Q♥ 9♥"},
+	}
+
+	for _, test := range tests {
+		if got := formatHand(cards(t, test.hand)); got != test.want {
+			t.Errorf("formatHand(%q) = %q, want %q", test.hand, got, test.want)
+		}
+	}
+
+	if got := formatMelds([][]pinochle.Card{cards(t, "KH QH"), cards(t, "9D")}); got != "[K♥ Q♥] [9♦]" {
+		t.Errorf("formatMelds wrote %q", got)
+	}
+}
+
+func TestPromptParsing(t *testing.T) {
+	card := promptMatch(t, 1).PlayerOneHand()[0]
+	typed := []string{
+		card.String(),
+		strings.ToLower(card.String()),
+		card.Symbol(),
+		"  " + card.String() + "  ",
+	}
+
+	if card.Rank() == pinochle.Ten {
+		typed = append(typed, "t"+string(card.Suit()))
+	}
+
+	for _, text := range typed {
+		match := promptMatch(t, 1)
+		p, out := newPromptUI(match, text+"\n")
+		if err := p.play(); err != nil {
+			t.Errorf("%q: %v\n%v", text, err, out)
+			continue
+		}
+
+		if n := len(match.PlayerOneHand()); n != 11 {
+			t.Errorf("%q was not played as %v: %v cards left in hand", text, card, n)
+		}
+	}
+}
+
+func TestPromptSession(t *testing.T) {
+	match := promptMatch(t, 1)
+	hand := append([]pinochle.Card(nil), match.PlayerOneHand()...)
+	var missing pinochle.Card
+	for _, suit := range []pinochle.Suit{pinochle.Spades, pinochle.Hearts, pinochle.Clubs, pinochle.Diamonds} {
+		candidate, _ := pinochle.NewCard(pinochle.Ace, suit)
+		if !contains(hand, candidate) {
+			missing = candidate
+			break
+		}
+	}
+
+	if pinochle.CompareCards(missing, pinochle.DummyCard) {
+		t.Skip("the hand holds every ace")
+	}
+
+	input := strings.Join([]string{"help", "XYZ", missing.String(), hand[0].String()}, "\n") + "\n"
+	p, out := newPromptUI(match, input)
+	if err := p.play(); err != nil {
+		t.Fatal(err)
+	}
+
+	session := out.String()
+	if !strings.Contains(session, "Your hand: "+formatHand(hand)) {
+		t.Errorf("the hand was not shown:\n%v", session)
+	}
+
+	if !strings.Contains(session, "Type cards as rank and suit") {
+		t.Errorf("help was not shown:\n%v", session)
+	}
+
+	if strings.Count(session, "Play a card: ") != 4 {
+		t.Errorf("the player should be asked 4 times:\n%v", session)
+	}
+
+	if !strings.Contains(session, `"XYZ" is not a card`) {
+		t.Errorf("a bad card was not reported:\n%v", session)
+	}
+
+	if left := match.PlayerOneHand(); len(left) != len(hand)-1 || count(left, hand[0]) != count(hand, hand[0])-1 {
+		t.Errorf("%v was not played: %v", hand[0], left)
+	}
+
+	p, _ = newPromptUI(promptMatch(t, 1), "quit\n")
+	if err := p.play(); err != errQuit {
+		t.Errorf("quit returned %v, want errQuit", err)
+	}
+
+	p, _ = newPromptUI(promptMatch(t, 1), "")
+	if err := p.play(); err != io.EOF {
+		t.Errorf("the end of input returned %v, want io.EOF", err)
+	}
+
+	p, out = newPromptUI(promptMatch(t, 1), "")
+	p.logf("=== Game %v ===", 2)
+	p.logf("%v", "done")
+	if out.String() != "\n=== Game 2 ===\ndone\n" {
+		t.Errorf("logf wrote %q", out.String())
+	}
+}
+
+func TestScreenHandle(t *testing.T) {
+	sc := &screen{}
+	for _, key := range []rune{keyLeft, keyRight} {
+		if done, err := sc.handle(key, 0); done || err != nil || sc.selected != 0 {
+			t.Errorf("key %v with an empty hand: %v, %v, selected %v", key, done, err, sc.selected)
+		}
+	}
+
+	sc.selected = 0
+	sc.handle(keyLeft, 3)
+	if sc.selected != 2 {
+		t.Errorf("left from the first card selected %v, want 2", sc.selected)
+	}
+
+	sc.handle(keyRight, 3)
+	if sc.selected != 0 {
+		t.Errorf("right from the last card selected %v, want 0", sc.selected)
+	}
+
+	if done, err := sc.handle('q', 3); !done || err != errQuit {
+		t.Errorf("q returned %v, %v", done, err)
+	}
+}
+
+func TestLogHeight(t *testing.T) {
+	sc := &screen{term: &terminal{rows: 30, cols: 80}}
+	if height := sc.logHeight(); height != 30-headerLines-reservedLines {
+		t.Errorf("a 30 row terminal has a log of %v lines", height)
+	}
+
+	sc.term.rows = 5
+	if height := sc.logHeight(); height != 1 {
+		t.Errorf("a short terminal should still show a line of log, got %v", height)
+	}
+}
+
+// failingFile fails every write, and its close with closeErr.
+type failingFile struct {
+	closes   int
+	closeErr error
+}
+
+func (f *failingFile) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func (f *failingFile) Close() error {
+	f.closes++
+	return f.closeErr
+}
+
+func TestCloseRecord(t *testing.T) {
+	file := &failingFile{closeErr: errors.New("bad descriptor")}
+	match := pinochle.InitializeMatch(&pinochle.Human{}, pinochle.NewComputer(pinochle.Easy), pinochle.ClassicRules)
+	record := pinochle.NewRecordWriter(file, &match, nil)
+	match.Subscribe(record)
+	if err := match.NewGameWithSeed(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := closeRecord(record, file); err == nil || err.Error() != "disk full" || file.closes != 1 {
+		t.Errorf("a failed write should be reported, and the file still closed: %v, %v closes", err, file.closes)
+	}
+
+	var out bytes.Buffer
+	record = pinochle.NewRecordWriter(&out, &match, nil)
+	if err := closeRecord(record, file); err == nil || err.Error() != "bad descriptor" {
+		t.Errorf("a failed close should be reported: %v", err)
+	}
+}
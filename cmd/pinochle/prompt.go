@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ClaytonMcCray/pinochle"
+)
+
+// promptUI asks for every decision with a line of input.
+type promptUI struct {
+	match *pinochle.Match
+	in    *bufio.Scanner
+	out   io.Writer
+}
+
+// play shows the table and asks for a card until Match accepts one.
+func (p *promptUI) play() error {
+	p.showTable(p.match.PlayerOneTable())
+	for {
+		line, err := p.prompt("Play a card: ")
+		if err != nil || p.match.MatchOver() {
+			return err
+		}
+
+		card, err := pinochle.ParseCard(line)
+		if err == nil {
+			err = p.match.PlayerOnePlayed(card)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		fmt.Fprintln(p.out, err)
+		if legal := p.match.PlayerOneLegalPlays(); len(legal) < len(p.match.PlayerOneHand()) {
+			fmt.Fprintln(p.out, "You may play:", formatCards(sortHand(legal)))
+		}
+	}
+}
+
+// meld asks for a meld until Match accepts one, or the player passes.
+func (p *promptUI) meld() error {
+	p.showTable(p.match.PlayerOneTable())
+	fmt.Fprintln(p.out, "You can meld with:", formatCards(sortHand(p.match.PlayerOneMeldableCards())))
+	for {
+		line, err := p.prompt("Meld (cards, \"dix\", or enter to pass): ")
+		if err != nil || line == "" || p.match.MatchOver() {
+			return err
+		}
+
+		if strings.EqualFold(line, "dix") {
+			if err := p.match.PlayerOneExchangeDix(); err != nil {
+				fmt.Fprintln(p.out, err)
+				continue
+			}
+
+			return nil
+		}
+
+		cards, err := pinochle.ParseCards(line)
+		if err != nil {
+			fmt.Fprintln(p.out, err)
+			continue
+		}
+
+		if p.match.PlayerOneMeld(cards) {
+			return nil
+		}
+
+		fmt.Fprintln(p.out, "That is not a meld you can make now.")
+	}
+}
+
+func (p *promptUI) logf(format string, args ...interface{}) {
+	if strings.HasPrefix(format, "===") {
+		fmt.Fprintln(p.out)
+	}
+
+	fmt.Fprintf(p.out, format+"\n", args...)
+}
+
+func (p *promptUI) trickDone() {}
+
+// prompt reads a line of input, handling the commands that work at any prompt.
+func (p *promptUI) prompt(text string) (string, error) {
+	for {
+		fmt.Fprint(p.out, text)
+		if !p.in.Scan() {
+			if err := p.in.Err(); err != nil {
+				return "", err
+			}
+
+			return "", io.EOF
+		}
+
+		line := strings.TrimSpace(p.in.Text())
+		switch strings.ToLower(line) {
+		case "quit":
+			return "", errQuit
+		case "out":
+			if _, err := p.match.PlayerOneDeclareOut(); err != nil {
+				fmt.Fprintln(p.out, err)
+				continue
+			}
+
+			return "", nil
+		case "help", "?":
+			fmt.Fprintln(p.out, "Type cards as rank and suit, such as 10H, QS or A♦; \"out\" declares out, \"quit\" leaves.")
+			continue
+		}
+
+		return line, nil
+	}
+}
+
+// showTable shows what playerOne sees before deciding.
+func (p *promptUI) showTable(table pinochle.Table) {
+	fmt.Fprintln(p.out)
+	fmt.Fprintln(p.out, scores(p.match))
+	fmt.Fprintf(p.out, "Trump %v, %v in the stock\n", table.Trump.Symbol(), plural(table.StockSize, "card"))
+	if len(table.OpponentMelds) > 0 {
+		fmt.Fprintln(p.out, "Computer's melds:", formatMelds(table.OpponentMelds))
+	}
+
+	if len(table.Melds) > 0 {
+		fmt.Fprintln(p.out, "Your melds:      ", formatMelds(table.Melds))
+	}
+
+	if !pinochle.CompareCards(table.Led, pinochle.DummyCard) {
+		fmt.Fprintln(p.out, "The Computer leads", table.Led.Symbol())
+	}
+
+	fmt.Fprintln(p.out, "Your hand:", formatHand(table.Hand))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Keys that don't stand for themselves are read as negative runes.
+const (
+	keyUp rune = -1 - iota
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyEscape
+	keyEnter
+	keyInterrupt
+)
+
+// terminal is the controlling terminal, switched by stty to read keys as they
+// are pressed and to leave them unechoed, and drawn on with ANSI escapes in the
+// alternate screen.
+type terminal struct {
+	saved      string
+	in         *bufio.Reader
+	out        io.Writer
+	rows, cols int
+}
+
+// openTerminal takes over the terminal until close.
+func openTerminal() (*terminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("standard input is not a terminal: %v", err)
+	}
+
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return nil, err
+	}
+
+	t := &terminal{saved: saved, in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	t.measure()
+	fmt.Fprint(t.out, "\x1b[?1049h\x1b[?25l")
+	return t, nil
+}
+
+// close gives the terminal back as it was found.
+func (t *terminal) close() {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	stty(t.saved)
+}
+
+// size returns the rows and columns of the terminal as last measured.
+func (t *terminal) size() (rows, cols int) {
+	return t.rows, t.cols
+}
+
+// measure asks stty for the size of the terminal, taking 24 by 80 if it can't
+// tell. It is run when a key is pressed rather than for every redraw, so a
+// resize shows from the next key.
+func (t *terminal) measure() {
+	t.rows, t.cols = 24, 80
+	out, err := stty("size")
+	if err != nil {
+		return
+	}
+
+	var rows, cols int
+	if n, _ := fmt.Sscan(out, &rows, &cols); n == 2 && rows > 0 && cols > 0 {
+		t.rows, t.cols = rows, cols
+	}
+}
+
+// readKey returns the next key pressed.
+func (t *terminal) readKey() (rune, error) {
+	r, _, err := t.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	t.measure()
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 3: // ctrl-c, which -isig leaves to us
+		return keyInterrupt, nil
+	case 0x1b:
+	default:
+		return r, nil
+	}
+
+	// A lone escape is the escape key; otherwise it starts a sequence such as
+	// ESC [ A for the up arrow, or ESC [ 5 ~ for page up.
+	if t.in.Buffered() == 0 {
+		return keyEscape, nil
+	}
+
+	if r, _, err = t.in.ReadRune(); err != nil || (r != '[' && r != 'O') {
+		return keyEscape, err
+	}
+
+	r, _, err = t.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	switch r {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'C':
+		return keyRight, nil
+	case 'D':
+		return keyLeft, nil
+	case '5', '6':
+		if next, _, err := t.in.ReadRune(); err != nil || next != '~' {
+			return keyEscape, err
+		}
+
+		if r == '5' {
+			return keyPageUp, nil
+		}
+
+		return keyPageDown, nil
+	}
+
+	return keyEscape, nil
+}
+
+// stty runs stty on the terminal and returns its output.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClaytonMcCray/pinochle"
+)
+
+// ANSI select graphic rendition codes.
+const (
+	sgrBold      = "1"
+	sgrDim       = "2"
+	sgrUnderline = "4"
+	sgrReverse   = "7"
+	sgrRed       = "31"
+	sgrYellow    = "33"
+	sgrCyan      = "36"
+)
+
+const (
+	modeWatch = iota // nothing to decide
+	modePlay
+	modeMeld
+)
+
+// headerLines is how many lines the screen draws above the log, down to and
+// including its title.
+const headerLines = 12
+
+// The last row of the terminal is left empty, so that the newline ending the
+// last line drawn doesn't scroll the screen.
+const reservedLines = 1
+
+// screen is the full-screen ui: the table at the top, with the hand picked from
+// by the arrow keys, and a scrollable log of the match below.
+type screen struct {
+	match *pinochle.Match
+	term  *terminal
+	delay time.Duration
+
+	mode     int
+	selected int          // index into the sorted hand
+	marked   map[int]bool // cards of the sorted hand marked to meld
+	status   string
+	log      []string
+	scroll   int  // how many lines the log is scrolled back
+	blink    bool // hides the winning card of the last trick
+}
+
+// runScreen plays s in a full-screen ui, and waits for a key when the match is
+// over so the result can be read.
+func runScreen(s *session, delay time.Duration) error {
+	term, err := openTerminal()
+	if err != nil {
+		return err
+	}
+
+	defer term.close()
+	sc := &screen{match: s.match, term: term, delay: delay}
+	s.ui = sc
+	if err := s.run(); err != nil {
+		return err
+	}
+
+	sc.mode = modeWatch
+	sc.status = "The match is over. Press any key to leave."
+	sc.draw()
+	_, err = term.readKey()
+	return err
+}
+
+// play lets the player pick a card with the arrow keys until Match accepts one.
+func (sc *screen) play() error {
+	sc.mode, sc.marked, sc.status = modePlay, nil, ""
+	defer func() { sc.mode = modeWatch }()
+	for {
+		hand := sc.hand()
+		sc.draw()
+		key, err := sc.term.readKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case keyEnter, ' ':
+			if err := sc.match.PlayerOnePlayed(hand[sc.selected]); err != nil {
+				sc.status = err.Error()
+				continue
+			}
+
+			sc.status = ""
+			return nil
+		default:
+			if done, err := sc.handle(key, len(hand)); done || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// meld lets the player mark the cards of a meld and make it, exchange the dix,
+// or pass.
+func (sc *screen) meld() error {
+	sc.mode, sc.marked = modeMeld, make(map[int]bool)
+	sc.status = "You won the trick and may meld."
+	defer func() { sc.mode, sc.marked = modeWatch, nil }()
+	hand := sc.hand()
+	for {
+		sc.draw()
+		key, err := sc.term.readKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case ' ':
+			sc.marked[sc.selected] = !sc.marked[sc.selected]
+		case keyEnter:
+			var cards []pinochle.Card
+			for i, card := range hand {
+				if sc.marked[i] {
+					cards = append(cards, card)
+				}
+			}
+
+			if len(cards) == 0 || sc.match.PlayerOneMeld(cards) {
+				sc.status = ""
+				return nil
+			}
+
+			sc.status = "That is not a meld you can make now."
+		case 'd', 'D':
+			if err := sc.match.PlayerOneExchangeDix(); err != nil {
+				sc.status = err.Error()
+				continue
+			}
+
+			sc.status = ""
+			return nil
+		case 'p', 'P', keyEscape:
+			sc.status = ""
+			return nil
+		default:
+			if done, err := sc.handle(key, len(hand)); done || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handle deals with the keys that work whatever is being decided. It returns
+// true once the decision no longer needs making.
+func (sc *screen) handle(key rune, handSize int) (bool, error) {
+	switch key {
+	case keyLeft, keyRight:
+		if handSize == 0 {
+			break
+		}
+
+		step := 1
+		if key == keyLeft {
+			step = handSize - 1
+		}
+
+		sc.selected = (sc.selected + step) % handSize
+	case keyUp:
+		sc.scroll++
+	case keyDown:
+		sc.scroll--
+	case keyPageUp:
+		sc.scroll += sc.logHeight()
+	case keyPageDown:
+		sc.scroll -= sc.logHeight()
+	case 'o', 'O':
+		if _, err := sc.match.PlayerOneDeclareOut(); err != nil {
+			sc.status = err.Error()
+			return false, nil
+		}
+
+		return true, nil
+	case 'q', 'Q', keyInterrupt:
+		return true, errQuit
+	}
+
+	return false, nil
+}
+
+func (sc *screen) logf(format string, args ...interface{}) {
+	sc.log = append(sc.log, fmt.Sprintf(format, args...))
+	sc.scroll = 0
+	sc.draw()
+}
+
+// trickDone blinks the card that took the trick.
+func (sc *screen) trickDone() {
+	if sc.delay <= 0 {
+		return
+	}
+
+	step := sc.delay / 6
+	for i := 0; i < 3; i++ {
+		sc.blink = true
+		sc.draw()
+		time.Sleep(step)
+		sc.blink = false
+		sc.draw()
+		time.Sleep(step)
+	}
+}
+
+// hand returns playerOne's hand as it is drawn, and keeps the selection on it.
+func (sc *screen) hand() []pinochle.Card {
+	hand := sortHand(sc.match.PlayerOneHand())
+	if sc.selected >= len(hand) {
+		sc.selected = len(hand) - 1
+	}
+
+	if sc.selected < 0 {
+		sc.selected = 0
+	}
+
+	return hand
+}
+
+func (sc *screen) logHeight() int {
+	rows, _ := sc.term.size()
+	height := rows - headerLines - reservedLines
+	if height < 1 {
+		return 1
+	}
+
+	return height
+}
+
+// draw redraws the whole screen.
+func (sc *screen) draw() {
+	table := sc.match.PlayerOneTable()
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	add("%v  %v", style(" Pinochle ", sgrBold, sgrReverse), scores(sc.match))
+	stock := fmt.Sprintf("%v in the stock", plural(table.StockSize, "card"))
+	if trickPhase, lastCard := sc.match.TrickPhase(); !trickPhase {
+		stock = "the playoff"
+	} else if lastCard {
+		stock += style(", the last", sgrYellow)
+	}
+
+	add("Trump %v   %v", renderCard(table.Trump), stock)
+	add("")
+	add("Computer  %v in hand   melds %v", plural(table.OpponentHandSize, "card"), renderMelds(table.OpponentMelds))
+	add("          %v", sc.renderTrick(table))
+	add("You       melds %v", renderMelds(table.Melds))
+	add("")
+	add("Hand      %v", sc.renderHand(table))
+	add("")
+	add("%v", style(sc.status, sgrYellow))
+	add("%v", style(sc.help(), sgrDim))
+	add("")
+
+	height := sc.logHeight()
+	if max := len(sc.log) - height; sc.scroll > max {
+		sc.scroll = max
+	}
+
+	if sc.scroll < 0 {
+		sc.scroll = 0
+	}
+
+	title := "── Log "
+	if sc.scroll > 0 {
+		title += fmt.Sprintf("(%v back) ", plural(sc.scroll, "line"))
+	}
+
+	lines[len(lines)-1] = style(title+strings.Repeat("─", 20), sgrCyan)
+	end := len(sc.log) - sc.scroll
+	start := end - height
+	if start < 0 {
+		start = 0
+	}
+
+	lines = append(lines, sc.log[start:end]...)
+
+	var frame strings.Builder
+	frame.WriteString("\x1b[H")
+	for _, line := range lines {
+		frame.WriteString(line)
+		frame.WriteString("\x1b[K\n")
+	}
+
+	frame.WriteString("\x1b[J")
+	fmt.Fprint(sc.term.out, frame.String())
+}
+
+// renderTrick draws the card the Computer led when the player has to answer
+// it, and otherwise the last trick with the card that took it in bold.
+func (sc *screen) renderTrick(table pinochle.Table) string {
+	if !pinochle.CompareCards(table.Led, pinochle.DummyCard) {
+		return "Trick  " + renderCard(table.Led) + " (Computer)"
+	}
+
+	trick := sc.match.LastTrick()
+	if trick == nil {
+		return "Trick"
+	}
+
+	winner, _ := trick.Resolve()
+	var plays []string
+	for i, card := range trick.Plays() {
+		text := renderCard(card)
+		if trick.Seat(i) == winner {
+			text = style(card.Symbol(), cardColour(card), sgrBold)
+			if sc.blink {
+				text = strings.Repeat(" ", len([]rune(card.Symbol())))
+			}
+		}
+
+		plays = append(plays, text+" ("+name(trick.Seat(i))+")")
+	}
+
+	return "Last   " + strings.Join(plays, "  ")
+}
+
+// renderHand draws the sorted hand with the selected card in reverse video,
+// marked cards underlined, and the cards that can't be chosen dimmed.
+func (sc *screen) renderHand(table pinochle.Table) string {
+	var usable []pinochle.Card
+	switch sc.mode {
+	case modePlay:
+		usable = sc.match.PlayerOneLegalPlays()
+	case modeMeld:
+		usable = sc.match.PlayerOneMeldableCards()
+	}
+
+	var out strings.Builder
+	hand := sortHand(table.Hand)
+	for i, card := range hand {
+		if i > 0 {
+			out.WriteString(" ")
+			if card.Suit() != hand[i-1].Suit() {
+				out.WriteString("  ")
+			}
+		}
+
+		codes := []string{cardColour(card)}
+		if sc.mode != modeWatch && !contains(usable, card) {
+			codes = append(codes, sgrDim)
+		}
+
+		if sc.marked[i] {
+			codes = append(codes, sgrUnderline, sgrBold)
+		}
+
+		if sc.mode != modeWatch && i == sc.selected {
+			codes = append(codes, sgrReverse)
+		}
+
+		out.WriteString(style(card.Symbol(), codes...))
+	}
+
+	return out.String()
+}
+
+// help lists the keys that do something now.
+func (sc *screen) help() string {
+	switch sc.mode {
+	case modePlay:
+		return "←/→ choose   enter play   o declare out   ↑/↓ PgUp/PgDn scroll log   q quit"
+	case modeMeld:
+		return "←/→ choose   space mark   enter meld marked   d dix   p pass   o declare out   q quit"
+	}
+
+	return ""
+}
+
+func renderCard(card pinochle.Card) string {
+	return style(card.Symbol(), cardColour(card))
+}
+
+func renderMelds(melds [][]pinochle.Card) string {
+	groups := make([]string, len(melds))
+	for i, meld := range melds {
+		cards := make([]string, len(meld))
+		for j, card := range meld {
+			cards[j] = renderCard(card)
+		}
+
+		groups[i] = "[" + strings.Join(cards, " ") + "]"
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// cardColour returns red for hearts and diamonds, and the default colour for
+// spades and clubs.
+func cardColour(card pinochle.Card) string {
+	if card.Suit() == pinochle.Hearts || card.Suit() == pinochle.Diamonds {
+		return sgrRed
+	}
+
+	return ""
+}
+
+// style wraps text in the SGR codes given, and resets them after it.
+func style(text string, codes ...string) string {
+	var set []string
+	for _, code := range codes {
+		if code != "" {
+			set = append(set, code)
+		}
+	}
+
+	if len(set) == 0 || text == "" {
+		return text
+	}
+
+	return "\x1b[" + strings.Join(set, ";") + "m" + text + "\x1b[0m"
+}
+
+func contains(cards []pinochle.Card, card pinochle.Card) bool {
+	for _, c := range cards {
+		if pinochle.CompareCards(c, card) {
+			return true
+		}
+	}
+
+	return false
+}
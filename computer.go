@@ -1,65 +1,324 @@
 package pinochle
 
-import "errors"
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
 
-// Computer is an object satisfying the player interface.
+// Computer is a Player that decides for itself. By default it plays by
+// heuristics: it takes tricks that carry counters, ducks tricks that don't with
+// its least useful card, holds on to its meld combinations while there is a
+// stock, and always plays legally. Setting Iterations or Budget makes it search
+// instead, and play the playoff perfectly; see NewComputer for ready-made
+// strengths.
 type Computer struct {
-	hand              []Card
-	currentMeldScore  int
-	currentTrickScore int
-	currentScore      int
-	melds             [][]Card
+	// Iterations caps the number of sampled deals per play; 0 means no cap.
+	Iterations int
+	// Budget caps the time spent per play; 0 means no cap.
+	Budget time.Duration
+	// Seed seeds the sampling, so that searches can be reproduced; 0 means
+	// a seed taken from the clock.
+	Seed int64
+
+	rng *rand.Rand
 }
 
-func (c *Computer) storeMeld(meld []Card) {
-	c.melds = append(c.melds, meld)
+// Play returns the card the Computer chooses to play.
+func (c *Computer) Play(table Table) (Card, error) {
+	if len(table.Legal) == 0 {
+		return DummyCard, errors.New("hand of Computer is empty")
+	}
+
+	// The search only knows the two-handed game.
+	if c.searching() && table.Players <= 2 {
+		if table.StockSize == 0 {
+			return c.solve(table), nil
+		}
+
+		return c.search(table), nil
+	}
+
+	return c.playHeuristic(table), nil
 }
 
-func (c *Computer) scoreTrickPoints(points int) {
-	c.currentTrickScore += points
+// playHeuristic chooses a card from table.Legal by heuristics alone.
+func (c *Computer) playHeuristic(table Table) Card {
+	values := table.values()
+	keep := c.keepValues(table, values)
+	if CompareCards(table.Led, DummyCard) {
+		return c.lead(table, keep)
+	}
+
+	return c.follow(table, values, keep)
 }
 
-func (c *Computer) scoreMeldPoints(points int) {
-	c.currentMeldScore += points
+// Meld returns the most valuable meld the Computer can make, if any. Between
+// melds of equal value it shows the fewest cards.
+func (c *Computer) Meld(table Table) ([]Card, error) {
+	var best []Card
+	bestPoints := 0
+	for _, meld := range legalMelds(table.Hand, table.Melds, table.Trump.suit, table.values()) {
+		if meld.points > bestPoints || (meld.points == bestPoints && len(meld.cards) < len(best)) {
+			best = meld.cards
+			bestPoints = meld.points
+		}
+	}
+
+	if best == nil {
+		return nil, nil
+	}
+
+	return append([]Card(nil), best...), nil
 }
 
-func (c *Computer) getMelds() [][]Card {
-	return c.melds
+// keepValues rates how much the Computer would lose by giving up each card of
+// its hand: its counters, its share of the melds it helps build while melding
+// is still possible, and a premium for trump.
+func (c *Computer) keepValues(table Table, values points) map[Card]int {
+	trumpSuit := table.Trump.suit
+	keep := make(map[Card]int)
+	for _, card := range table.Hand {
+		keep[card] = values.cardPoints(card) + faceValueRanks[card.faceValue]
+		if card.suit == trumpSuit {
+			keep[card] += values.ten
+		}
+	}
+
+	if table.StockSize == 0 {
+		return keep
+	}
+
+	slices := newMeldSlices(trumpSuit)
+	for _, meld := range slices.withPoints(values) {
+		held := heldCount(table.Hand, meld.cards)
+		if held == 0 || meld.points == 0 {
+			continue
+		}
+
+		// A lone card of a larger meld is barely worth keeping; a complete one
+		// is worth its full value.
+		worth := meld.points
+		if len(meld.cards) > 1 {
+			worth = meld.points * (held - 1) / (len(meld.cards) - 1)
+		}
+
+		for card := range keep {
+			if containsCards(meld.cards, []Card{card}) {
+				keep[card] += worth
+			}
+		}
+	}
+
+	return keep
 }
 
-func (c *Computer) hasCards() bool {
-	return len(c.hand) > 0
+// heldCount returns how many cards of meld are in hand, counting duplicates.
+func heldCount(hand, meld []Card) int {
+	counts := make(map[Card]int)
+	for _, card := range hand {
+		counts[card]++
+	}
+
+	held := 0
+	for _, card := range meld {
+		if counts[card] > 0 {
+			counts[card]--
+			held++
+		}
+	}
+
+	return held
 }
 
-func (c *Computer) mergeMeldsAndTricks() {
-	c.currentScore = c.currentMeldScore + c.currentTrickScore
+// lead plays a sure non-trump ace in the playoff, and otherwise the least
+// useful card.
+func (c *Computer) lead(table Table, keep map[Card]int) Card {
+	if table.StockSize == 0 {
+		for _, card := range table.Legal {
+			if card.faceValue == "A" && card.suit != table.Trump.suit {
+				return card
+			}
+		}
+	}
+
+	return cheapest(table.Legal, keep)
 }
 
-func (c *Computer) meldScore() int {
-	return c.currentMeldScore
+// follow takes the trick with the cheapest winning card when it is worth
+// taking, and otherwise ducks with the cheapest losing card.
+func (c *Computer) follow(table Table, values points, keep map[Card]int) Card {
+	trick := table.Trick
+	if len(trick) == 0 {
+		trick = []Card{table.Led}
+	}
+
+	winning := trick[trickWinner(trick, table.Trump.suit)]
+	worth := 0
+	for _, card := range trick {
+		worth += values.cardPoints(card)
+	}
+
+	var winners, losers []Card
+	for _, card := range table.Legal {
+		if beats(card, winning, table.Trump.suit) {
+			winners = append(winners, card)
+		} else {
+			losers = append(losers, card)
+		}
+	}
+
+	if len(winners) == 0 {
+		return cheapest(losers, keep)
+	}
+
+	winner := cheapest(winners, keep)
+	if len(losers) == 0 {
+		return winner
+	}
+
+	// Tricks are always worth taking in the playoff, and when the cards in
+	// them carry ten or more points. Otherwise only win with a card of the led
+	// suit, which brings its own counters home without spending a trump.
+	worthTaking := table.StockSize == 0 || worth >= values.ten
+	if worthTaking || winner.suit == table.Led.suit {
+		return winner
+	}
+
+	return cheapest(losers, keep)
 }
 
-func (c *Computer) score() int {
-	c.mergeMeldsAndTricks()
-	return c.currentScore
+// beats reports whether card, played after led, takes the trick from it.
+func beats(card, led Card, trumpSuit string) bool {
+	if card.suit == led.suit {
+		return faceValueRanks[card.faceValue] > faceValueRanks[led.faceValue]
+	}
+
+	return card.suit == trumpSuit
 }
 
-func (c *Computer) pushToHand(card Card) {
-	c.hand = append(c.hand, card)
+// cheapest returns the card of cards with the lowest keep value.
+func cheapest(cards []Card, keep map[Card]int) Card {
+	best := cards[0]
+	for _, card := range cards[1:] {
+		if keep[card] < keep[best] {
+			best = card
+		}
+	}
+
+	return best
 }
 
-func (c *Computer) getHand() []Card {
-	return c.hand
+// Bid bids the minimum when the Computer expects to make it in its best suit,
+// and passes otherwise.
+func (c *Computer) Bid(table Table, minimum int) int {
+	if _, estimate := c.bestTrump(table); estimate >= minimum {
+		return minimum
+	}
+
+	return 0
+}
+
+// NameTrump names the suit the Computer expects to score most in.
+func (c *Computer) NameTrump(table Table) Suit {
+	suit, _ := c.bestTrump(table)
+	return Suit(suit)
 }
 
-// play for Computer takes in a DummyCard for the sake of satisfying
-// the player interface. It returns the card the Computer chooses to play.
-func (c *Computer) play(card Card) (Card, error) {
-	if c.hasCards() {
-		temp := c.hand[len(c.hand)-1]
-		c.hand = removeCard(c.hand, len(c.hand)-1)
-		return temp, nil
+// bestTrump returns the suit in which the Computer expects to score most, and
+// how much: its meld, a fair share of the counters, and more or less for every
+// trump and ace it holds above or below its share of them.
+func (c *Computer) bestTrump(table Table) (string, int) {
+	values := table.values()
+	players := table.Players
+	if players < 2 {
+		players = 2
+	}
+
+	deck := table.cards()
+	counters := values.lastTrick
+	for _, face := range deck.faces {
+		counters += deck.copies * len(suits) * values.cardPoints(Card{face, ""})
+	}
+
+	deckSize := deck.size()
+	strongCards := deck.copies * (len(deck.faces) + len(suits) - 1)
+	best, bestEstimate := suits[0], 0
+	for i, suit := range suits {
+		strength := 0
+		for _, card := range table.Hand {
+			if card.suit == suit || card.faceValue == "A" {
+				strength++
+			}
+		}
+
+		estimate := bestMelds(table.Hand, nil, suit, values).Total + counters/players
+		estimate += (strength*deckSize - len(table.Hand)*strongCards) * values.ten / deckSize
+		if i == 0 || estimate > bestEstimate {
+			best, bestEstimate = suit, estimate
+		}
+	}
+
+	return best, bestEstimate
+}
+
+// Bury discards the cards whose loss costs the Computer the least meld, and of
+// those the lowest, keeping trump.
+func (c *Computer) Bury(table Table, count int) []Card {
+	values := table.values()
+	trumpSuit := table.Trump.suit
+	hand := append([]Card(nil), table.Hand...)
+	var buried []Card
+	for len(buried) < count && len(hand) > 0 {
+		meld := bestMelds(hand, nil, trumpSuit, values).Total
+		pick, pickCost := 0, 0
+		for i, card := range hand {
+			rest := append(append([]Card(nil), hand[:i]...), hand[i+1:]...)
+			cost := meld - bestMelds(rest, nil, trumpSuit, values).Total + faceValueRanks[card.faceValue]
+			if card.suit == trumpSuit {
+				cost += values.ten
+			}
+
+			if i == 0 || cost < pickCost {
+				pick, pickCost = i, cost
+			}
+		}
+
+		buried = append(buried, hand[pick])
+		hand = removeCard(hand, pick)
+	}
+
+	return buried
+}
+
+// PassCards passes the bidder the Computer's highest trump, and then its
+// highest other cards. The bidder passes back what Bury would discard.
+func (c *Computer) PassCards(table Table, count int, toBidder bool) []Card {
+	if !toBidder {
+		return c.Bury(table, count)
+	}
+
+	power := func(card Card) int {
+		if card.suit == table.Trump.suit {
+			return len(faceValues) + faceValueRanks[card.faceValue]
+		}
+
+		return faceValueRanks[card.faceValue]
+	}
+
+	hand := append([]Card(nil), table.Hand...)
+	var passed []Card
+	for len(passed) < count && len(hand) > 0 {
+		pick := 0
+		for i, card := range hand {
+			if power(card) > power(hand[pick]) {
+				pick = i
+			}
+		}
+
+		passed = append(passed, hand[pick])
+		hand = removeCard(hand, pick)
 	}
 
-	return card, errors.New("hand of Computer is empty")
+	return passed
 }
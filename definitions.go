@@ -2,22 +2,71 @@ package pinochle
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
-	"time"
+	"strings"
+	"unicode"
 )
 
-type player interface {
-	pushToHand(card Card)
-	getHand() []Card
-	score() int
-	getMelds() [][]Card
-	hasCards() bool
-	play(card Card) (Card, error)
-	scoreTrickPoints(points int)
-	scoreMeldPoints(points int)
-	mergeMeldsAndTricks()
-	meldScore() int
-	storeMeld(meld []Card)
+// Player makes the decisions for one seat of a Match. The Match keeps the seat's
+// hand, melds and scores, so a Player only has to choose.
+type Player interface {
+	// Play returns the card to play; it must be one of table.Legal.
+	Play(table Table) (Card, error)
+	// Meld returns the meld to make after winning a trick, or nil to pass.
+	Meld(table Table) ([]Card, error)
+}
+
+// Bidder is a Player that can also bid, name trump and bury in an AuctionMatch.
+type Bidder interface {
+	Player
+	// Bid returns the bid to make, at least minimum, or 0 to pass.
+	Bid(table Table, minimum int) int
+	// NameTrump returns the trump suit for table.Hand, which holds the widow.
+	NameTrump(table Table) Suit
+	// Bury returns count cards of table.Hand to discard.
+	Bury(table Table, count int) []Card
+	// PassCards returns count cards of table.Hand to pass to a partner, who is
+	// the bidder when toBidder is true.
+	PassCards(table Table, count int, toBidder bool) []Card
+}
+
+// Table is everything a Player can see when it has to make a decision.
+type Table struct {
+	Hand      []Card
+	Legal     []Card
+	Led       Card // DummyCard when the player is leading
+	Trump     Card
+	StockSize int
+	Melds     [][]Card
+	Seen      []Card // every card played this game, in order
+	Trick     []Card // the cards played to the current trick, in order
+	Players   int    // the number of players at the table
+
+	OpponentMelds    [][]Card
+	OpponentShown    []Card // melded cards still in the opponent's hand
+	OpponentHandSize int
+
+	pointValues points
+	deck        deckSpec
+}
+
+// values returns the point values of the Match the Table was built for.
+func (table Table) values() points {
+	if table.pointValues == (points{}) {
+		return initializePoints()
+	}
+
+	return table.pointValues
+}
+
+// cards returns the deck the Table's game is played with.
+func (table Table) cards() deckSpec {
+	if table.deck.copies == 0 {
+		return singleDeck
+	}
+
+	return table.deck
 }
 
 // Card is the fundamental type for each playing card.
@@ -29,7 +78,118 @@ type Card struct {
 // DummyCard is a blank place holder for compliance with player interface.
 var DummyCard = Card{"", ""}
 
-// Deck is a slice of cards representing the stack. It satisfies the Shuffler interface.
+// Rank is the face value of a card.
+type Rank string
+
+// The ranks of a pinochle deck, from highest to lowest.
+const (
+	Ace   Rank = "A"
+	Ten   Rank = "10"
+	King  Rank = "K"
+	Queen Rank = "Q"
+	Jack  Rank = "J"
+	Nine  Rank = "9"
+)
+
+// Suit is the suit of a card.
+type Suit string
+
+// The suits of a pinochle deck.
+const (
+	Spades   Suit = "S"
+	Diamonds Suit = "D"
+	Clubs    Suit = "C"
+	Hearts   Suit = "H"
+)
+
+var suitSymbols = map[Suit]string{Spades: "♠", Diamonds: "♦", Clubs: "♣", Hearts: "♥"}
+
+// Symbol returns the unicode symbol of the suit, such as ♥ for Hearts.
+func (suit Suit) Symbol() string {
+	return suitSymbols[suit]
+}
+
+// NewCard returns the card of rank and suit. It returns an error unless both
+// are found in a pinochle deck.
+func NewCard(rank Rank, suit Suit) (Card, error) {
+	if _, ok := faceValueRanks[string(rank)]; !ok {
+		return DummyCard, fmt.Errorf("%q is not a pinochle rank", rank)
+	}
+
+	if _, ok := suitSymbols[suit]; !ok {
+		return DummyCard, fmt.Errorf("%q is not a suit", suit)
+	}
+
+	return Card{string(rank), string(suit)}, nil
+}
+
+// ParseCard parses a card written as its rank followed by its suit, such as
+// "10H", "QS" or "A♦". Case is ignored, and T may stand for the ten.
+func ParseCard(text string) (Card, error) {
+	runes := []rune(strings.ToUpper(strings.TrimSpace(text)))
+	if len(runes) < 2 {
+		return DummyCard, fmt.Errorf("%q is not a card", text)
+	}
+
+	rank, suit := string(runes[:len(runes)-1]), string(runes[len(runes)-1])
+	for known, symbol := range suitSymbols {
+		if suit == symbol {
+			suit = string(known)
+		}
+	}
+
+	if rank == "T" {
+		rank = string(Ten)
+	}
+
+	card, err := NewCard(Rank(rank), Suit(suit))
+	if err != nil {
+		return DummyCard, fmt.Errorf("%q is not a card: %v", text, err)
+	}
+
+	return card, nil
+}
+
+// ParseCards parses cards separated by spaces or commas, such as "KH QH".
+func ParseCards(text string) ([]Card, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	var cards []Card
+	for _, field := range fields {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+// Rank returns the card's rank.
+func (card Card) Rank() Rank {
+	return Rank(card.faceValue)
+}
+
+// Suit returns the card's suit.
+func (card Card) Suit() Suit {
+	return Suit(card.suit)
+}
+
+// String returns the card as ParseCard reads it, such as "10H".
+func (card Card) String() string {
+	return card.faceValue + card.suit
+}
+
+// Symbol returns the card with the symbol of its suit, such as "10♥".
+func (card Card) Symbol() string {
+	return card.faceValue + card.Suit().Symbol()
+}
+
+// Deck is a slice of cards representing the stack, and the trump card turned up under it.
 type Deck struct {
 	stack []Card
 	trump Card
@@ -64,29 +224,72 @@ var faceValueRanks = map[string]int{
 	faceValues[5]: len(faceValues) - 5,
 }
 
-// buildDeck generates a Deck; it shuffle determines whether or not the Deck is shuffled.
-func buildDeck(shuffle bool) Deck {
+// Shuffler rearranges a stack of cards in place. Supplying one to a Match
+// decides every shuffled deal.
+type Shuffler interface {
+	Shuffle(stack []Card)
+}
+
+// SeededShuffler is a Shuffler driven by a seeded math/rand source, so that the
+// deals it makes can be replayed from the seed.
+type SeededShuffler struct {
+	rng *rand.Rand
+}
+
+// NewSeededShuffler returns a SeededShuffler seeded with seed.
+func NewSeededShuffler(seed int64) *SeededShuffler {
+	return &SeededShuffler{rand.New(rand.NewSource(seed))}
+}
+
+// Shuffle permutes stack.
+func (s *SeededShuffler) Shuffle(stack []Card) {
+	s.rng.Shuffle(len(stack), func(i, j int) {
+		stack[i], stack[j] = stack[j], stack[i]
+	})
+}
+
+// deckSpec describes the cards of a deck: copies of every face in faces, in
+// every suit.
+type deckSpec struct {
+	faces  []string
+	copies int
+}
+
+// singleDeck is the 48-card pinochle deck, and doubleDeck the 80-card one,
+// which has no nines.
+var (
+	singleDeck = deckSpec{faceValues, 2}
+	doubleDeck = deckSpec{faceValues[:5], 4}
+)
+
+// size returns the number of cards in the deck.
+func (spec deckSpec) size() int {
+	return len(spec.faces) * len(suits) * spec.copies
+}
+
+// build generates a Deck; it is shuffled by shuffler unless shuffler is nil.
+func (spec deckSpec) build(shuffler Shuffler) Deck {
 	var stack []Card
 	for _, suit := range suits {
-		for _, face := range faceValues {
-			stack = append(stack, Card{face, suit}, Card{face, suit})
+		for _, face := range spec.faces {
+			for i := 0; i < spec.copies; i++ {
+				stack = append(stack, Card{face, suit})
+			}
 		}
 	}
 
-	if shuffle {
-		var shuffledStack = make([]Card, len(stack))
-		rand.Seed(time.Now().UTC().UnixNano())
-		perm := rand.Perm(len(stack))
-		for srcIdx, destIdx := range perm {
-			shuffledStack[destIdx] = stack[srcIdx]
-		}
-
-		return Deck{shuffledStack, DummyCard}
+	if shuffler != nil {
+		shuffler.Shuffle(stack)
 	}
 
 	return Deck{stack, DummyCard}
 }
 
+// buildDeck generates a single Deck; it is shuffled by shuffler unless shuffler is nil.
+func buildDeck(shuffler Shuffler) Deck {
+	return singleDeck.build(shuffler)
+}
+
 func removeCard(hand []Card, idx int) []Card {
 	return append(hand[:idx], hand[idx+1:]...)
 }
@@ -132,6 +335,24 @@ func compareCardSlices(first, second []Card) bool {
 	return true
 }
 
+// containsCards determines whether or not hand holds every card of cards, counting
+// duplicates.
+func containsCards(hand, cards []Card) bool {
+	counts := make(map[Card]int)
+	for _, card := range hand {
+		counts[card]++
+	}
+
+	for _, card := range cards {
+		counts[card]--
+		if counts[card] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Top level points container.
 type points struct {
 	ace   int
@@ -140,14 +361,37 @@ type points struct {
 	queen int
 	jack  int
 
+	lastTrick int
+
 	classA
 	classB
 	classC
 }
 
+// cardPoints returns the counter value of card.
+func (values points) cardPoints(card Card) int {
+	switch card.faceValue {
+	case "A":
+		return values.ace
+	case "10":
+		return values.ten
+	case "K":
+		return values.king
+	case "Q":
+		return values.queen
+	case "J":
+		return values.jack
+	}
+
+	return 0
+}
+
 // Construct holding Class A meld points.
 type classA struct {
 	flush         int
+	doubleRun     int
+	tripleRun     int
+	quadrupleRun  int
 	royalMarriage int
 	marriage      int
 	dix           int
@@ -155,30 +399,179 @@ type classA struct {
 
 // Construct holding Class B meld points.
 type classB struct {
-	hundredAces int
-	eightyKings int
-	sixtyQueens int
-	fortyJacks  int
+	hundredAces       int
+	eightyKings       int
+	sixtyQueens       int
+	fortyJacks        int
+	thousandAces      int
+	eightHundredKings int
+	sixHundredQueens  int
+	fourHundredJacks  int
+
+	// Only a double deck holds enough cards for these.
+	tripleAces      int
+	tripleKings     int
+	tripleQueens    int
+	tripleJacks     int
+	quadrupleAces   int
+	quadrupleKings  int
+	quadrupleQueens int
+	quadrupleJacks  int
 }
 
 // Construct holding Class C meld points.
 type classC struct {
-	pinochle       int
-	doublePinochle int
+	pinochle          int
+	doublePinochle    int
+	triplePinochle    int
+	quadruplePinochle int
 }
 
 type validMeldSlices struct {
-	flush           []Card
-	royalMarriage   []Card
-	spadeMarriage   []Card
-	clubMarriage    []Card
-	heartMarriage   []Card
-	diamondMarriage []Card
-	dix             []Card
-	hundredAces     []Card
-	eightyKings     []Card
-	sixtyQueens     []Card
-	fortyJacks      []Card
-	pinochle        []Card
-	doublePinochle  []Card
+	flush             []Card
+	doubleRun         []Card
+	royalMarriage     []Card
+	spadeMarriage     []Card
+	clubMarriage      []Card
+	heartMarriage     []Card
+	diamondMarriage   []Card
+	dix               []Card
+	hundredAces       []Card
+	eightyKings       []Card
+	sixtyQueens       []Card
+	fortyJacks        []Card
+	thousandAces      []Card
+	eightHundredKings []Card
+	sixHundredQueens  []Card
+	fourHundredJacks  []Card
+	pinochle          []Card
+	doublePinochle    []Card
+
+	tripleRun         []Card
+	quadrupleRun      []Card
+	tripleAces        []Card
+	tripleKings       []Card
+	tripleQueens      []Card
+	tripleJacks       []Card
+	quadrupleAces     []Card
+	quadrupleKings    []Card
+	quadrupleQueens   []Card
+	quadrupleJacks    []Card
+	triplePinochle    []Card
+	quadruplePinochle []Card
+}
+
+// newMeldSlices builds the melds available when suit is trump.
+func newMeldSlices(suit string) validMeldSlices {
+	slices := validMeldSlices{
+		flush:           []Card{Card{"A", suit}, Card{"10", suit}, Card{"K", suit}, Card{"Q", suit}, Card{"J", suit}},
+		royalMarriage:   []Card{Card{"K", suit}, Card{"Q", suit}},
+		clubMarriage:    []Card{Card{"K", "C"}, Card{"Q", "C"}},
+		spadeMarriage:   []Card{Card{"K", "S"}, Card{"Q", "S"}},
+		diamondMarriage: []Card{Card{"K", "D"}, Card{"Q", "D"}},
+		heartMarriage:   []Card{Card{"K", "H"}, Card{"Q", "H"}},
+		dix:             []Card{Card{"9", suit}},
+		hundredAces:     []Card{Card{"A", "S"}, Card{"A", "H"}, Card{"A", "C"}, Card{"A", "D"}},
+		eightyKings:     []Card{Card{"K", "S"}, Card{"K", "H"}, Card{"K", "C"}, Card{"K", "D"}},
+		sixtyQueens:     []Card{Card{"Q", "S"}, Card{"Q", "H"}, Card{"Q", "C"}, Card{"Q", "D"}},
+		fortyJacks:      []Card{Card{"J", "S"}, Card{"J", "H"}, Card{"J", "C"}, Card{"J", "D"}},
+		pinochle:        []Card{Card{"Q", "S"}, Card{"J", "D"}},
+	}
+
+	slices.doubleRun = doubled(slices.flush)
+	slices.thousandAces = doubled(slices.hundredAces)
+	slices.eightHundredKings = doubled(slices.eightyKings)
+	slices.sixHundredQueens = doubled(slices.sixtyQueens)
+	slices.fourHundredJacks = doubled(slices.fortyJacks)
+	slices.doublePinochle = doubled(slices.pinochle)
+
+	slices.tripleRun = repeated(slices.flush, 3)
+	slices.quadrupleRun = repeated(slices.flush, 4)
+	slices.tripleAces = repeated(slices.hundredAces, 3)
+	slices.tripleKings = repeated(slices.eightyKings, 3)
+	slices.tripleQueens = repeated(slices.sixtyQueens, 3)
+	slices.tripleJacks = repeated(slices.fortyJacks, 3)
+	slices.quadrupleAces = repeated(slices.hundredAces, 4)
+	slices.quadrupleKings = repeated(slices.eightyKings, 4)
+	slices.quadrupleQueens = repeated(slices.sixtyQueens, 4)
+	slices.quadrupleJacks = repeated(slices.fortyJacks, 4)
+	slices.triplePinochle = repeated(slices.pinochle, 3)
+	slices.quadruplePinochle = repeated(slices.pinochle, 4)
+	return slices
+}
+
+// doubled returns two copies of every card of cards.
+func doubled(cards []Card) []Card {
+	return repeated(cards, 2)
+}
+
+// repeated returns n copies of every card of cards.
+func repeated(cards []Card, n int) []Card {
+	var copies []Card
+	for i := 0; i < n; i++ {
+		copies = append(copies, cards...)
+	}
+
+	return copies
+}
+
+// meldClass identifies which of the classA, classB and classC constructs a meld belongs to.
+type meldClass int
+
+const (
+	meldClassA meldClass = iota
+	meldClassB
+	meldClassC
+)
+
+// valuedMeld pairs the cards of a meld with its name, the points it scores and its class.
+type valuedMeld struct {
+	name   string
+	cards  []Card
+	points int
+	class  meldClass
+}
+
+// withPoints pairs every meld with its name and value from values. The royal
+// marriage is not also counted as a plain marriage.
+func (slices *validMeldSlices) withPoints(values points) []valuedMeld {
+	marriage := func(cards []Card) int {
+		if compareCardSlices(cards, slices.royalMarriage) {
+			return 0
+		}
+		return values.marriage
+	}
+
+	return []valuedMeld{
+		{"quadruple run", slices.quadrupleRun, values.quadrupleRun, meldClassA},
+		{"triple run", slices.tripleRun, values.tripleRun, meldClassA},
+		{"double run", slices.doubleRun, values.doubleRun, meldClassA},
+		{"run", slices.flush, values.flush, meldClassA},
+		{"royal marriage", slices.royalMarriage, values.royalMarriage, meldClassA},
+		{"marriage", slices.spadeMarriage, marriage(slices.spadeMarriage), meldClassA},
+		{"marriage", slices.clubMarriage, marriage(slices.clubMarriage), meldClassA},
+		{"marriage", slices.heartMarriage, marriage(slices.heartMarriage), meldClassA},
+		{"marriage", slices.diamondMarriage, marriage(slices.diamondMarriage), meldClassA},
+		{"dix", slices.dix, values.dix, meldClassA},
+		{"quadruple aces", slices.quadrupleAces, values.quadrupleAces, meldClassB},
+		{"quadruple kings", slices.quadrupleKings, values.quadrupleKings, meldClassB},
+		{"quadruple queens", slices.quadrupleQueens, values.quadrupleQueens, meldClassB},
+		{"quadruple jacks", slices.quadrupleJacks, values.quadrupleJacks, meldClassB},
+		{"triple aces", slices.tripleAces, values.tripleAces, meldClassB},
+		{"triple kings", slices.tripleKings, values.tripleKings, meldClassB},
+		{"triple queens", slices.tripleQueens, values.tripleQueens, meldClassB},
+		{"triple jacks", slices.tripleJacks, values.tripleJacks, meldClassB},
+		{"double aces", slices.thousandAces, values.thousandAces, meldClassB},
+		{"double kings", slices.eightHundredKings, values.eightHundredKings, meldClassB},
+		{"double queens", slices.sixHundredQueens, values.sixHundredQueens, meldClassB},
+		{"double jacks", slices.fourHundredJacks, values.fourHundredJacks, meldClassB},
+		{"aces around", slices.hundredAces, values.hundredAces, meldClassB},
+		{"kings around", slices.eightyKings, values.eightyKings, meldClassB},
+		{"queens around", slices.sixtyQueens, values.sixtyQueens, meldClassB},
+		{"jacks around", slices.fortyJacks, values.fortyJacks, meldClassB},
+		{"quadruple pinochle", slices.quadruplePinochle, values.quadruplePinochle, meldClassC},
+		{"triple pinochle", slices.triplePinochle, values.triplePinochle, meldClassC},
+		{"double pinochle", slices.doublePinochle, values.doublePinochle, meldClassC},
+		{"pinochle", slices.pinochle, values.pinochle, meldClassC},
+	}
 }
@@ -0,0 +1,91 @@
+package pinochle
+
+// Event is something that happened in a Match. Observers are sent one of the
+// types below; seats are numbered as in LastTrick, with playerOne as seat 0 and
+// playerTwo as seat 1.
+type Event interface {
+	event()
+}
+
+// CardDealt is sent for every card dealt to a hand, from the deal of a new game
+// through every draw from the stock, and the trump card taken by the last draw.
+type CardDealt struct {
+	Seat int
+	Card Card
+}
+
+// TrumpTurned is sent when the card under the stock is turned up for trump,
+// once the hands are dealt.
+type TrumpTurned struct {
+	Card Card
+}
+
+// CardPlayed is sent for every card played to a trick.
+type CardPlayed struct {
+	Seat int
+	Card Card
+}
+
+// TrickWon is sent when a trick is decided. Points are the counters in it; the
+// last trick bonus is only scored with the game, in HandScored.
+type TrickWon struct {
+	Seat   int
+	Trick  *Trick
+	Points int
+}
+
+// MeldDeclared is sent for every meld scored, including a dix exchanged for
+// the turned-up trump card, which sets Exchanged, and a dix turned up for trump
+// that the dealer scores under DixDealerScores.
+type MeldDeclared struct {
+	Seat      int
+	Meld      MeldItem
+	Exchanged bool
+}
+
+// HandScored is sent when a game is scored into the match, at the end of its
+// playoff or when a player declares out.
+type HandScored struct {
+	Score HandScore
+}
+
+// GameOver is sent once, when the match is over.
+type GameOver struct {
+	Outcome        Outcome
+	PlayerOneScore int
+	PlayerTwoScore int
+}
+
+func (CardDealt) event()    {}
+func (TrumpTurned) event()  {}
+func (CardPlayed) event()   {}
+func (TrickWon) event()     {}
+func (MeldDeclared) event() {}
+func (HandScored) event()   {}
+func (GameOver) event()     {}
+
+// Observer is told of every Event in a Match, in the order they happen. It is
+// called synchronously, so it sees the Match as the event left it, and should
+// hand anything slow to another goroutine.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc lets an ordinary function be an Observer.
+type ObserverFunc func(event Event)
+
+// Observe calls f(event).
+func (f ObserverFunc) Observe(event Event) {
+	f(event)
+}
+
+// Subscribe adds observer to those told of the Match's events.
+func (match *Match) Subscribe(observer Observer) {
+	match.observers = append(match.observers, observer)
+}
+
+func (match *Match) emit(event Event) {
+	for _, observer := range match.observers {
+		observer.Observe(event)
+	}
+}
@@ -1,74 +1,30 @@
 package pinochle
 
-import "fmt"
+import "errors"
 
-// Human is an object satisfying the player interface.
+// Human is a Player whose decisions come from outside the package. Frontends
+// usually pass its cards straight to Match.PlayerOnePlayed and friends; when a
+// Match is driven by Run, the decisions come from Chooser and Melder instead.
+// A nil Melder never melds.
 type Human struct {
-	hand              []Card
-	currentTrickScore int
-	currentMeldScore  int
-	currentScore      int
-	melds             [][]Card
+	Chooser func(table Table) Card
+	Melder  func(table Table) []Card
 }
 
-func (h *Human) storeMeld(meld []Card) {
-	h.melds = append(h.melds, meld)
-}
-
-func (h *Human) scoreTrickPoints(points int) {
-	h.currentTrickScore += points
-}
-
-func (h *Human) scoreMeldPoints(points int) {
-	h.currentMeldScore += points
-}
-
-func (h *Human) mergeMeldsAndTricks() {
-	h.currentScore = h.currentMeldScore + h.currentTrickScore
-}
-
-func (h *Human) meldScore() int {
-	return h.currentMeldScore
-}
-
-func (h *Human) hasCards() bool {
-	return len(h.hand) > 0
-}
-
-func (h *Human) getMelds() [][]Card {
-	return h.melds
-}
-
-func (h *Human) score() int {
-	h.mergeMeldsAndTricks()
-	return h.currentScore
-}
-
-func (h *Human) pushToHand(card Card) {
-	h.hand = append(h.hand, card)
-}
+// Play asks the Chooser for the card to play.
+func (h *Human) Play(table Table) (Card, error) {
+	if h.Chooser == nil {
+		return DummyCard, errors.New("Human has no Chooser to decide its play")
+	}
 
-func (h *Human) getHand() []Card {
-	return h.hand
+	return h.Chooser(table), nil
 }
 
-func (h *Human) handContains(card Card) (bool, int) {
-	for idx, cardInHand := range h.hand {
-		if CompareCards(card, cardInHand) {
-			return true, idx
-		}
+// Meld asks the Melder for the meld to make, if any.
+func (h *Human) Meld(table Table) ([]Card, error) {
+	if h.Melder == nil {
+		return nil, nil
 	}
-	return false, -1
-}
 
-// play for Human takes in the card the human wants to play,
-// validates it, and returns the card if it's a valid card. If it's
-// not a valid card, a non-nil error will be returned.
-func (h *Human) play(card Card) (Card, error) {
-	success, idx := h.handContains(card)
-	if success {
-		h.hand = removeCard(h.hand, idx)
-		return card, nil
-	}
-	return DummyCard, fmt.Errorf("hand of Human does not contain card %v", card)
+	return h.Melder(table), nil
 }
@@ -3,15 +3,18 @@ package pinochle
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"time"
 )
 
-// InitializeMatch will build a Match and return it
-func InitializeMatch(pOne, pTwo player, playingTo int) Match {
+// InitializeMatch will build a Match played under rules and return it
+func InitializeMatch(pOne, pTwo Player, rules Rules) Match {
 	match := Match{
-		pointValues:     initializePoints(),
-		playerOne:       pOne,
-		playerTwo:       pTwo,
-		playingTo:       playingTo,
+		rules:           rules,
+		pointValues:     rules.points(),
+		playerOne:       &seat{player: pOne},
+		playerTwo:       &seat{player: pTwo},
+		playingTo:       rules.PlayingTo,
 		dealerPlayerOne: true,
 	}
 
@@ -20,9 +23,10 @@ func InitializeMatch(pOne, pTwo player, playingTo int) Match {
 
 // Match is the pinochle game controller
 type Match struct {
+	rules              Rules
 	pointValues        points
-	playerOne          player
-	playerTwo          player
+	playerOne          *seat
+	playerTwo          *seat
 	deck               Deck
 	dealerPlayerOne    bool
 	playerOneWonTrick  bool
@@ -30,32 +34,115 @@ type Match struct {
 	playingTo          int
 	mostRecentlyPlayed [2]Card
 	meldSlices         validMeldSlices
+	meldWindowOpen     bool
+	meldedThisTrick    bool
+	meldSuccessful     bool
+	trickUnassigned    bool
+	trickDecided       bool
+	playerOneCaptured  []Card
+	playerTwoCaptured  []Card
+	inTrick            [2]bool
+	lastTrick          *Trick
+	played             []Card
+	shuffler           Shuffler
+	seeds              *rand.Rand
+	seed               int64
+	history            []HandScore
+	over               bool
+	playerOneWonMatch  bool
+	declaredOut        bool
+	wrongDeclaration   bool
+	observers          []Observer
+}
+
+// HandScore records what each player scored in one game, and the score that
+// wins the match after it.
+type HandScore struct {
+	PlayerOneMeld   int
+	PlayerOneTricks int
+	PlayerTwoMeld   int
+	PlayerTwoTricks int
+	PlayingTo       int
 }
 
 // NewGame initializes a new game, consisting of a trick phase and a playoff.
+// A shuffled game uses the Shuffler given to SetShuffler if there is one, and
+// otherwise a fresh seed, which Seed reports afterwards.
 func (match *Match) NewGame(shuffle bool) error {
-	match.deck = buildDeck(shuffle)
+	if !shuffle {
+		match.seed = 0
+		return match.newGame(nil)
+	}
+
+	if match.shuffler != nil {
+		match.seed = 0
+		return match.newGame(match.shuffler)
+	}
+
+	if match.seeds == nil {
+		match.seeds = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	}
+
+	return match.NewGameWithSeed(match.seeds.Int63())
+}
+
+// NewGameWithSeed initializes a new game shuffled by a SeededShuffler seeded
+// with seed. With the same dealer, the same seed always replays the same deal.
+func (match *Match) NewGameWithSeed(seed int64) error {
+	match.seed = seed
+	return match.newGame(NewSeededShuffler(seed))
+}
+
+// SetSeed makes the seeds of every following shuffled NewGame derive from seed,
+// so that a whole match can be replayed.
+func (match *Match) SetSeed(seed int64) {
+	match.seeds = rand.New(rand.NewSource(seed))
+}
+
+// SetShuffler makes every following shuffled NewGame use shuffler.
+func (match *Match) SetShuffler(shuffler Shuffler) {
+	match.shuffler = shuffler
+}
+
+// Seed returns the seed the current game was shuffled with. It is 0 for games
+// that were not shuffled, or were shuffled by a Shuffler given to SetShuffler.
+func (match *Match) Seed() int64 {
+	return match.seed
+}
+
+func (match *Match) newGame(shuffler Shuffler) error {
+	match.deck = buildDeck(shuffler)
+	match.playerOneCaptured = nil
+	match.playerTwoCaptured = nil
+	match.inTrick = [2]bool{}
+	match.mostRecentlyPlayed = [2]Card{DummyCard, DummyCard}
+	match.trickDecided, match.trickUnassigned = false, false
+	match.lastTrick = nil
+	match.played = nil
+	match.playerOne.melds = nil
+	match.playerTwo.melds = nil
+	match.playerOne.meldItems = nil
+	match.playerTwo.meldItems = nil
+	match.playerOne.currentMeldScore, match.playerOne.currentTrickScore = 0, 0
+	match.playerTwo.currentMeldScore, match.playerTwo.currentTrickScore = 0, 0
+	dealer := 1
+	if match.dealerPlayerOne {
+		dealer = 0
+	}
+
 	err := match.deal()
 	match.playerOneLed = match.dealerPlayerOne
 	match.buildMeldSlices()
+	if err == nil && match.rules.Dix == DixDealerScores && match.deck.trump.faceValue == "9" {
+		match.seatAt(dealer).scoreMeldPoints(match.pointValues.dix)
+		match.emit(MeldDeclared{Seat: dealer, Meld: MeldItem{"dix", []Card{match.deck.trump}, match.pointValues.dix}})
+	}
+
 	return err
 }
 
 func (match *Match) buildMeldSlices() {
-	suit := match.deck.trump.suit
-	match.meldSlices.flush = []Card{Card{"A", suit}, Card{"10", suit}, Card{"K", suit}, Card{"Q", suit}, Card{"J", suit}}
-	match.meldSlices.royalMarriage = []Card{Card{"K", suit}, Card{"Q", suit}}
-	match.meldSlices.clubMarriage = []Card{Card{"K", "C"}, Card{"Q", "C"}}
-	match.meldSlices.spadeMarriage = []Card{Card{"K", "S"}, Card{"Q", "S"}}
-	match.meldSlices.diamondMarriage = []Card{Card{"K", "D"}, Card{"Q", "D"}}
-	match.meldSlices.heartMarriage = []Card{Card{"K", "H"}, Card{"Q", "H"}}
-	match.meldSlices.dix = []Card{Card{"9", suit}}
-	match.meldSlices.hundredAces = []Card{Card{"A", "S"}, Card{"A", "H"}, Card{"A", "C"}, Card{"A", "D"}}
-	match.meldSlices.eightyKings = []Card{Card{"K", "S"}, Card{"K", "H"}, Card{"K", "C"}, Card{"K", "D"}}
-	match.meldSlices.sixtyQueens = []Card{Card{"Q", "S"}, Card{"Q", "H"}, Card{"Q", "C"}, Card{"Q", "D"}}
-	match.meldSlices.fortyJacks = []Card{Card{"J", "S"}, Card{"J", "H"}, Card{"J", "C"}, Card{"Q", "D"}}
-	match.meldSlices.pinochle = []Card{Card{"Q", "S"}, Card{"J", "D"}}
-	match.meldSlices.doublePinochle = []Card{Card{"Q", "S"}, Card{"J", "D"}, Card{"Q", "S"}, Card{"J", "D"}}
+	match.meldSlices = newMeldSlices(match.deck.trump.suit)
 }
 
 // deal will deal cards to playerOne and playerTwo.
@@ -69,7 +156,7 @@ func (match *Match) deal() error {
 					return err
 				}
 
-				match.playerTwo.pushToHand(card)
+				match.dealCard(1, card)
 			}
 
 			for j := 0; j < 3; j++ {
@@ -78,7 +165,7 @@ func (match *Match) deal() error {
 					return err
 				}
 
-				match.playerOne.pushToHand(card)
+				match.dealCard(0, card)
 			}
 
 		} else {
@@ -89,7 +176,7 @@ func (match *Match) deal() error {
 					return err
 				}
 
-				match.playerOne.pushToHand(card)
+				match.dealCard(0, card)
 			}
 
 			for j := 0; j < 3; j++ {
@@ -98,7 +185,7 @@ func (match *Match) deal() error {
 					return err
 				}
 
-				match.playerTwo.pushToHand(card)
+				match.dealCard(1, card)
 			}
 		}
 	}
@@ -109,9 +196,16 @@ func (match *Match) deal() error {
 		return err
 	}
 	match.deck.trump = card
+	match.emit(TrumpTurned{card})
 	return nil
 }
 
+// dealCard adds card to the hand of the player at idx of match.mostRecentlyPlayed.
+func (match *Match) dealCard(idx int, card Card) {
+	match.seatAt(idx).pushToHand(card)
+	match.emit(CardDealt{idx, card})
+}
+
 // PlayerOneHand accesses the interface method player.getHand()
 func (match *Match) PlayerOneHand() []Card {
 	return match.playerOne.getHand()
@@ -122,11 +216,167 @@ func (match *Match) PlayerTwoHand() []Card {
 	return match.playerTwo.getHand()
 }
 
-// MatchOver returns whether or not one of the players but not both has reached the cap.
+// MatchOver returns whether or not one of the players has won the match.
 func (match *Match) MatchOver() bool {
-	pOneWins := match.playerOne.meldScore() >= match.playingTo
-	pTwoWins := match.playerTwo.meldScore() >= match.playingTo
-	return (pOneWins || pTwoWins) && !(pOneWins && pTwoWins)
+	return match.over
+}
+
+// PlayerOneWonMatch returns whether or not the match is over and playerOne won it.
+func (match *Match) PlayerOneWonMatch() bool {
+	return match.over && match.playerOneWonMatch
+}
+
+// PlayerOneWonGame returns whether or not playerOne outscored playerTwo in the
+// most recently finished game.
+func (match *Match) PlayerOneWonGame() bool {
+	if len(match.history) == 0 {
+		return false
+	}
+
+	last := match.history[len(match.history)-1]
+	return last.PlayerOneMeld+last.PlayerOneTricks > last.PlayerTwoMeld+last.PlayerTwoTricks
+}
+
+// PlayerOneScore returns playerOne's points from every finished game.
+func (match *Match) PlayerOneScore() int {
+	return match.playerOne.score()
+}
+
+// PlayerTwoScore returns playerTwo's points from every finished game.
+func (match *Match) PlayerTwoScore() int {
+	return match.playerTwo.score()
+}
+
+// Rules returns the rules the match is played under.
+func (match *Match) Rules() Rules {
+	return match.rules
+}
+
+// PlayingTo returns the score that wins the match. It rises by the rules'
+// TargetRaise every time both players reach it in the same game.
+func (match *Match) PlayingTo() int {
+	return match.playingTo
+}
+
+// GameScore returns the melds and counters scored so far in the game in progress.
+func (match *Match) GameScore() HandScore {
+	return HandScore{
+		PlayerOneMeld:   match.playerOne.currentMeldScore,
+		PlayerOneTricks: match.playerOne.currentTrickScore,
+		PlayerTwoMeld:   match.playerTwo.currentMeldScore,
+		PlayerTwoTricks: match.playerTwo.currentTrickScore,
+		PlayingTo:       match.playingTo,
+	}
+}
+
+// ScoreHistory returns the scores of every finished game, in order.
+func (match *Match) ScoreHistory() []HandScore {
+	return match.history
+}
+
+// scoreGame adds the finished game to the history and the players' scores, and
+// decides the match if one player alone has reached playingTo. When both have,
+// neither declared out, so play goes on to a target raised by TargetRaise, or
+// the higher score wins if the rules don't raise the target.
+func (match *Match) scoreGame() {
+	match.recordGame()
+	pOneOut := match.playerOne.score() >= match.playingTo
+	pTwoOut := match.playerTwo.score() >= match.playingTo
+	if pOneOut && pTwoOut && match.rules.TargetRaise == 0 {
+		pOneOut = match.playerOne.score() > match.playerTwo.score()
+		pTwoOut = match.playerTwo.score() > match.playerOne.score()
+	} else if pOneOut && pTwoOut {
+		for match.playerOne.score() >= match.playingTo && match.playerTwo.score() >= match.playingTo {
+			match.playingTo += match.rules.TargetRaise
+		}
+
+		pOneOut = match.playerOne.score() >= match.playingTo
+		pTwoOut = match.playerTwo.score() >= match.playingTo
+		match.history[len(match.history)-1].PlayingTo = match.playingTo
+	}
+
+	if pOneOut || pTwoOut {
+		match.over = true
+		match.playerOneWonMatch = pOneOut
+	}
+
+	match.announceScore()
+}
+
+// announceScore tells observers of the game just scored, and of the end of the
+// match if it is over.
+func (match *Match) announceScore() {
+	match.emit(HandScored{match.history[len(match.history)-1]})
+	if match.over {
+		match.emit(GameOver{match.Outcome(), match.playerOne.score(), match.playerTwo.score()})
+	}
+}
+
+// recordGame adds the game in progress to the history and the players' scores.
+func (match *Match) recordGame() {
+	match.history = append(match.history, match.GameScore())
+
+	match.playerOne.mergeMeldsAndTricks()
+	match.playerTwo.mergeMeldsAndTricks()
+}
+
+// PlayerOneDeclareOut claims that playerOne's score, with the melds and counters
+// of the game in progress, has reached playingTo. The match ends at once: won by
+// playerOne if the claim is right, and lost if it isn't. It returns whether the
+// claim was right.
+func (match *Match) PlayerOneDeclareOut() (bool, error) {
+	return match.declareOut(match.playerOne, true)
+}
+
+// PlayerTwoDeclareOut claims that playerTwo's score, with the melds and counters
+// of the game in progress, has reached playingTo. The match ends at once: won by
+// playerTwo if the claim is right, and lost if it isn't. It returns whether the
+// claim was right.
+func (match *Match) PlayerTwoDeclareOut() (bool, error) {
+	return match.declareOut(match.playerTwo, false)
+}
+
+func (match *Match) declareOut(p *seat, playerOne bool) (bool, error) {
+	if match.over {
+		return false, errors.New("the match is already over")
+	}
+
+	if !match.playerOne.hasCards() && !match.playerTwo.hasCards() {
+		return false, errors.New("there is no game in progress to declare out of")
+	}
+
+	correct := p.score()+p.gameScore() >= match.playingTo
+	match.recordGame()
+	match.over = true
+	match.declaredOut = true
+	match.wrongDeclaration = !correct
+	match.playerOneWonMatch = playerOne == correct
+	match.announceScore()
+	return correct, nil
+}
+
+// Outcome describes how a match ended.
+type Outcome struct {
+	Over         bool
+	PlayerOneWon bool
+	// DeclaredOut is true when a player ended the match by declaring out, and
+	// WrongDeclaration when that player had not in fact reached playingTo.
+	DeclaredOut      bool
+	WrongDeclaration bool
+}
+
+// Outcome returns how the match ended, if it has.
+func (match *Match) Outcome() Outcome {
+	if !match.over {
+		return Outcome{}
+	}
+
+	return Outcome{
+		Over:             true,
+		PlayerOneWon:     match.playerOneWonMatch,
+		DeclaredOut:      match.declaredOut,
+		WrongDeclaration: match.wrongDeclaration,
+	}
 }
 
 // PlayerOneMelds returns a slice of card slices; the internal slices are the individual melds
@@ -153,7 +403,7 @@ func (match *Match) DealToPlayerOne() error {
 		return err
 	}
 
-	match.playerOne.pushToHand(card)
+	match.dealCard(0, card)
 	return nil
 }
 
@@ -164,7 +414,7 @@ func (match *Match) DealToPlayerTwo() error {
 		return err
 	}
 
-	match.playerTwo.pushToHand(card)
+	match.dealCard(1, card)
 	return nil
 }
 
@@ -175,7 +425,7 @@ func (match *Match) DealTrumpToPlayerOne() error {
 		return err
 	}
 
-	match.playerOne.pushToHand(card)
+	match.dealCard(0, card)
 	return nil
 }
 
@@ -186,7 +436,7 @@ func (match *Match) DealTrumpToPlayerTwo() error {
 		return err
 	}
 
-	match.playerTwo.pushToHand(card)
+	match.dealCard(1, card)
 	return nil
 }
 
@@ -196,52 +446,207 @@ func (match *Match) Playoff() bool {
 	return (match.playerOne.hasCards() || match.playerTwo.hasCards()) && !trickPhase
 }
 
-// PlayerOneTurn returns the internal value dealerPlayerOne.
-/*
+// PlayerOneTurn returns whether playerOne plays the next card: to lead when it
+// won the last trick, or led before it, and otherwise to answer playerTwo's lead.
 func (match *Match) PlayerOneTurn() bool {
+	if match.inTrick[0] == match.inTrick[1] {
+		return match.playerOneLed
+	}
+
+	return !match.inTrick[0]
 }
-*/
 
-func (match *Match) storePlayerOneCard(card Card) {
-	match.mostRecentlyPlayed[0] = card
+// PlayerOneTable returns the game as playerOne's Player is shown it.
+func (match *Match) PlayerOneTable() Table {
+	return match.table(0)
 }
 
-func (match *Match) storePlayerTwoCard(card Card) {
-	match.mostRecentlyPlayed[1] = card
+// PlayerTwoTable returns the game as playerTwo's Player is shown it.
+func (match *Match) PlayerTwoTable() Table {
+	return match.table(1)
 }
 
-// PlayerOnePlayed validates the card that playerOne wants to play,
-// then stores it. An error is returned if playerOne's hand doesn't contain the card.
-func (match *Match) PlayerOnePlayed(card Card) error {
-	validatedCard, err := match.playerOne.play(card)
-	if err != nil {
-		return err
+// seatAt returns the seat stored at idx of match.mostRecentlyPlayed.
+func (match *Match) seatAt(idx int) *seat {
+	if idx == 0 {
+		return match.playerOne
 	}
 
-	match.storePlayerOneCard(validatedCard)
-	return nil
+	return match.playerTwo
+}
+
+// table builds the view of the game for the player at idx.
+func (match *Match) table(idx int) Table {
+	p := match.seatAt(idx)
+	opponent := match.seatAt(1 - idx)
+	led, answering := match.currentLead(idx)
+	var trick []Card
+	if answering {
+		trick = []Card{led}
+	}
+
+	return Table{
+		Hand:      append([]Card(nil), p.getHand()...),
+		Legal:     match.legalPlays(p.getHand(), idx),
+		Led:       led,
+		Trump:     match.deck.trump,
+		StockSize: len(match.deck.stack),
+		Melds:     p.getMelds(),
+		Seen:      append([]Card(nil), match.played...),
+		Trick:     trick,
+		Players:   2,
+
+		OpponentMelds:    opponent.getMelds(),
+		OpponentShown:    shownCards(opponent.getHand(), opponent.getMelds(), match.deck.trump.suit, match.pointValues),
+		OpponentHandSize: len(opponent.getHand()),
+
+		pointValues: match.pointValues,
+	}
+}
+
+// PlayerOnePlayed validates the card that playerOne wants to play,
+// then stores it. An error is returned if playerOne's hand doesn't contain the card,
+// or if the card breaks the playoff rules. Passing DummyCard lets playerOne's
+// Player choose the card.
+func (match *Match) PlayerOnePlayed(card Card) error {
+	return match.playCard(0, card)
 }
 
 // PlayerTwoPlayed validates the card that playerTwo wants to play,
-// then stores it. An error is returned if playerTwo's hand doesn't contain the card.
+// then stores it. An error is returned if playerTwo's hand doesn't contain the card,
+// or if the card breaks the playoff rules. Passing DummyCard lets playerTwo's
+// Player choose the card.
 func (match *Match) PlayerTwoPlayed(card Card) error {
-	validatedCard, err := match.playerTwo.play(card)
+	return match.playCard(1, card)
+}
+
+// playCard plays card for the seat at idx of match.mostRecentlyPlayed.
+func (match *Match) playCard(idx int, card Card) error {
+	if match.inTrick[0] && match.inTrick[1] {
+		match.inTrick = [2]bool{}
+		match.trickDecided = false
+	}
+
+	if match.inTrick[idx] {
+		return errors.New("player has already played to this trick")
+	}
+
+	s := match.seatAt(idx)
+	if CompareCards(card, DummyCard) {
+		var err error
+		card, err = s.player.Play(match.table(idx))
+		if err != nil {
+			return err
+		}
+	}
+
+	if led, following := match.ledCard(idx); following {
+		if err := match.ruleViolation(s.getHand(), led, card); err != nil {
+			return err
+		}
+	}
+
+	validatedCard, err := s.play(card)
 	if err != nil {
 		return err
 	}
 
-	match.storePlayerTwoCard(validatedCard)
+	match.meldWindowOpen = false
+	match.inTrick[idx] = true
+	match.mostRecentlyPlayed[idx] = validatedCard
+	match.played = append(match.played, validatedCard)
+	match.emit(CardPlayed{idx, validatedCard})
 	return nil
 }
 
-func (match *Match) handContains(hand []Card, card Card) bool {
+// currentLead returns the card the opponent of the player at idx led to the
+// current trick, if the player at idx still has to answer it.
+func (match *Match) currentLead(idx int) (led Card, answering bool) {
+	current := !(match.inTrick[0] && match.inTrick[1])
+	if !current || !match.inTrick[1-idx] || match.inTrick[idx] {
+		return DummyCard, false
+	}
+
+	return match.mostRecentlyPlayed[1-idx], true
+}
+
+// ledCard is currentLead restricted to the playoff, when the led card must be
+// answered according to the rules.
+func (match *Match) ledCard(idx int) (led Card, following bool) {
+	trickPhase, _ := match.TrickPhase()
+	led, answering := match.currentLead(idx)
+	if trickPhase || !answering {
+		return DummyCard, false
+	}
+
+	return led, true
+}
+
+// ruleViolation returns an error naming the rule broken when card is played from
+// hand in answer to led during the playoff.
+func (match *Match) ruleViolation(hand []Card, led, card Card) error {
+	return playoffViolation(hand, led, card, match.deck.trump.suit)
+}
+
+// playoffViolation returns an error naming the rule broken when card is played
+// from hand in answer to led during the playoff: follow suit if able, head the
+// trick if able, and otherwise trump if able.
+func playoffViolation(hand []Card, led, card Card, trumpSuit string) error {
+	var canFollow, canHead, canTrump bool
 	for _, c := range hand {
-		if CompareCards(c, card) {
-			return true
+		if c.suit == led.suit {
+			canFollow = true
+			if faceValueRanks[c.faceValue] > faceValueRanks[led.faceValue] {
+				canHead = true
+			}
+		}
+
+		if c.suit == trumpSuit {
+			canTrump = true
 		}
 	}
 
-	return false
+	if card.suit == led.suit {
+		if canHead && faceValueRanks[card.faceValue] <= faceValueRanks[led.faceValue] {
+			return fmt.Errorf("%v must head the trick: a higher card than %v is held", card, led)
+		}
+
+		return nil
+	}
+
+	if canFollow {
+		return fmt.Errorf("%v must follow suit: %v was led", card, led)
+	}
+
+	if canTrump && card.suit != trumpSuit {
+		return fmt.Errorf("%v must trump: no %v is held but trump %v is", card, led.suit, trumpSuit)
+	}
+
+	return nil
+}
+
+// PlayerOneLegalPlays returns the cards in playerOne's hand that may be played
+// to the current trick.
+func (match *Match) PlayerOneLegalPlays() []Card {
+	return match.legalPlays(match.playerOne.getHand(), 0)
+}
+
+// PlayerTwoLegalPlays returns the cards in playerTwo's hand that may be played
+// to the current trick.
+func (match *Match) PlayerTwoLegalPlays() []Card {
+	return match.legalPlays(match.playerTwo.getHand(), 1)
+}
+
+func (match *Match) legalPlays(hand []Card, idx int) []Card {
+	led, following := match.ledCard(idx)
+	var legal []Card
+	for _, card := range hand {
+		if !following || match.ruleViolation(hand, led, card) == nil {
+			legal = append(legal, card)
+		}
+	}
+
+	return legal
 }
 
 // CardsPlayedInTrick returns an array of the cards played in the most recent trick.
@@ -258,8 +663,17 @@ func (match *Match) setNextTrickLeader() {
 	match.playerOneLed = match.playerOneWonTrick
 }
 
-// DecideTrickWinner sets match.playerOneWonTrick based off of match.mostRecentlyPlayed.
+// DecideTrickWinner sets match.playerOneWonTrick based off of match.mostRecentlyPlayed,
+// resolving the two cards as a Trick led by whoever led. An error is returned
+// unless both players have played to the trick and it has not been decided yet.
 func (match *Match) DecideTrickWinner() error {
+	if !match.inTrick[0] || !match.inTrick[1] {
+		return errors.New("both players must play to the trick before it is decided")
+	}
+
+	if match.trickDecided {
+		return errors.New("the trick has already been decided")
+	}
 
 	defer match.setNextTrickLeader()
 
@@ -271,146 +685,244 @@ func (match *Match) DecideTrickWinner() error {
 		return errors.New("playerTwo's card not properly stored")
 	}
 
-	pOneCard := match.mostRecentlyPlayed[0]
-	pTwoCard := match.mostRecentlyPlayed[1]
 	trump, err := match.deck.getTrump()
 
 	if err != nil {
 		return err
 	}
 
-	if pOneCard.suit == trump.suit && pTwoCard.suit != trump.suit {
-		match.playerOneWonTrick = true
-		return nil
-	} else if pOneCard.suit != trump.suit && pTwoCard.suit == trump.suit {
-		match.playerOneWonTrick = false
-		return nil
-	} else {
-		if match.playerOneLed {
-			if pTwoCard.suit != pOneCard.suit {
-				match.playerOneWonTrick = true
-				return nil
-			} else if faceValueRanks[pOneCard.faceValue] >= faceValueRanks[pTwoCard.faceValue] {
-				match.playerOneWonTrick = true
-				return nil
-			} else {
-				match.playerOneWonTrick = false
-				return nil
-			}
-		} else {
-			if pOneCard.suit != pTwoCard.suit {
-				match.playerOneWonTrick = false
-				return nil
-			} else if faceValueRanks[pTwoCard.faceValue] >= faceValueRanks[pOneCard.faceValue] {
-				match.playerOneWonTrick = false
-				return nil
-			} else {
-				match.playerOneWonTrick = true
-				return nil
-			}
+	leader := 1
+	if match.playerOneLed {
+		leader = 0
+	}
+
+	trick := newTrick(leader, 2, trump.suit, match.pointValues)
+	trick.Play(match.mostRecentlyPlayed[leader])
+	trick.Play(match.mostRecentlyPlayed[1-leader])
+	winner, points := trick.Resolve()
+	match.playerOneWonTrick = winner == 0
+	match.lastTrick = trick
+	match.emit(TrickWon{winner, trick, points})
+
+	// The winner of a trick may meld once before the next card is played, but
+	// only while there are still cards in the stack.
+	trickPhase, _ := match.TrickPhase()
+	match.meldWindowOpen = trickPhase
+	match.meldedThisTrick = false
+	match.trickUnassigned = true
+	match.trickDecided = true
+	return nil
+}
+
+// LastTrick returns the most recently decided trick, with playerOne as seat 0
+// and playerTwo as seat 1, or nil before the first.
+func (match *Match) LastTrick() *Trick {
+	return match.lastTrick
+}
+
+// PlayerOneMeld attempts to meld attempt from playerOne's hand. It returns false
+// unless playerOne won the last trick, has not yet melded since, and attempt is a
+// single meld that the reuse rules allow. Melded cards stay in the hand and may
+// still be played, or melded again in a different class alongside a new card.
+func (match *Match) PlayerOneMeld(attempt []Card) bool {
+	return match.meld(0, match.playerOneWonTrick, attempt)
+}
+
+// PlayerTwoMeld attempts to meld attempt from playerTwo's hand. It returns false
+// unless playerTwo won the last trick, has not yet melded since, and attempt is a
+// single meld that the reuse rules allow. Melded cards stay in the hand and may
+// still be played, or melded again in a different class alongside a new card.
+func (match *Match) PlayerTwoMeld(attempt []Card) bool {
+	return match.meld(1, !match.playerOneWonTrick, attempt)
+}
+
+func (match *Match) meld(idx int, wonTrick bool, attempt []Card) bool {
+	p := match.seatAt(idx)
+	match.meldSuccessful = false
+	if !match.meldWindowOpen || !wonTrick {
+		return false
+	}
+
+	var item *MeldItem
+	for _, meld := range legalMelds(p.getHand(), p.getMelds(), match.deck.trump.suit, match.pointValues) {
+		if compareCardSlices(attempt, meld.cards) {
+			item = meld.item()
+			break
 		}
 	}
+
+	if item == nil {
+		return false
+	}
+
+	p.storeMeld(item.Cards)
+	p.meldItems = append(p.meldItems, *item)
+	p.scoreMeldPoints(item.Points)
+	match.emit(MeldDeclared{Seat: idx, Meld: *item})
+
+	match.meldWindowOpen = false
+	match.meldedThisTrick = true
+	match.meldSuccessful = true
+	return true
 }
 
-// validateMeld determines whether or not a real meld has been played, and then
-// returns the correct point value
-func (match *Match) validateMeld(attempt []Card) (int, error) {
-	var scoredPoints int
-	var err error
-	if compareCardSlices(attempt, match.meldSlices.flush) {
-		scoredPoints = match.pointValues.classA.flush
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.royalMarriage) {
-		scoredPoints = match.pointValues.classA.royalMarriage
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.clubMarriage) {
-		scoredPoints = match.pointValues.classA.marriage
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.diamondMarriage) {
-		scoredPoints = match.pointValues.classA.marriage
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.spadeMarriage) {
-		scoredPoints = match.pointValues.classA.marriage
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.heartMarriage) {
-		scoredPoints = match.pointValues.classA.marriage
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.dix) {
-		scoredPoints = match.pointValues.classA.dix
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.hundredAces) {
-		scoredPoints = match.pointValues.classB.hundredAces
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.eightyKings) {
-		scoredPoints = match.pointValues.classB.eightyKings
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.sixtyQueens) {
-		scoredPoints = match.pointValues.classB.sixtyQueens
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.fortyJacks) {
-		scoredPoints = match.pointValues.classB.fortyJacks
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.pinochle) {
-		scoredPoints = match.pointValues.classC.pinochle
-		err = nil
-	} else if compareCardSlices(attempt, match.meldSlices.doublePinochle) {
-		scoredPoints = match.pointValues.classC.doublePinochle
-		err = nil
-	} else {
-		scoredPoints = 0
-		err = errors.New("attempted meld is invalid")
+// PlayerOneExchangeDix swaps the nine of trump in playerOne's hand for the
+// turned-up trump card, and scores it as the dix. Like a meld, it is only
+// allowed right after playerOne wins a trick, and counts as its meld.
+func (match *Match) PlayerOneExchangeDix() error {
+	return match.exchangeDix(0, match.playerOneWonTrick)
+}
+
+// PlayerTwoExchangeDix swaps the nine of trump in playerTwo's hand for the
+// turned-up trump card, and scores it as the dix. Like a meld, it is only
+// allowed right after playerTwo wins a trick, and counts as its meld.
+func (match *Match) PlayerTwoExchangeDix() error {
+	return match.exchangeDix(1, !match.playerOneWonTrick)
+}
+
+func (match *Match) exchangeDix(idx int, wonTrick bool) error {
+	p := match.seatAt(idx)
+	match.meldSuccessful = false
+	if !match.meldWindowOpen || !wonTrick {
+		return errors.New("the dix can only be exchanged right after winning a trick")
+	}
+
+	if match.rules.Dix == DixNoExchange {
+		return errors.New("the dix cannot be exchanged under these rules")
+	}
+
+	dix := Card{"9", match.deck.trump.suit}
+	if CompareCards(match.deck.trump, dix) {
+		return errors.New("the turned-up trump card is already the dix")
+	}
+
+	var item *MeldItem
+	for _, meld := range legalMelds(p.getHand(), p.getMelds(), dix.suit, match.pointValues) {
+		if compareCardSlices(meld.cards, match.meldSlices.dix) {
+			item = meld.item()
+		}
 	}
 
-	return scoredPoints, err
+	if item == nil {
+		return fmt.Errorf("hand holds no unmelded %v", dix)
+	}
+
+	if _, err := p.play(dix); err != nil {
+		return err
+	}
+
+	p.pushToHand(match.deck.trump)
+	match.deck.trump = dix
+	p.storeMeld(item.Cards)
+	p.meldItems = append(p.meldItems, *item)
+	p.scoreMeldPoints(item.Points)
+	match.emit(MeldDeclared{Seat: idx, Meld: *item, Exchanged: true})
+
+	match.meldWindowOpen = false
+	match.meldedThisTrick = true
+	match.meldSuccessful = true
+	return nil
+}
+
+// MeldWasSuccessful reports whether the most recent meld attempt was accepted.
+func (match *Match) MeldWasSuccessful() bool {
+	return match.meldSuccessful
 }
 
-/*
-func (match *Match) AssignTrickPoints() {}
+// DoneMelding closes the melding window for the current trick and reports whether
+// the trick winner melded during it.
+func (match *Match) DoneMelding() bool {
+	match.meldWindowOpen = false
+	return match.meldedThisTrick
+}
+
+// PlayerOneMeldableCards returns the cards in playerOne's hand that complete at
+// least one meld the reuse rules allow.
+func (match *Match) PlayerOneMeldableCards() []Card {
+	return match.meldableCards(match.playerOne)
+}
+
+// PlayerTwoMeldableCards returns the cards in playerTwo's hand that complete at
+// least one meld the reuse rules allow.
+func (match *Match) PlayerTwoMeldableCards() []Card {
+	return match.meldableCards(match.playerTwo)
+}
+
+func (match *Match) meldableCards(p *seat) []Card {
+	inMeld := make(map[Card]bool)
+	for _, meld := range legalMelds(p.getHand(), p.getMelds(), match.deck.trump.suit, match.pointValues) {
+		for _, card := range meld.cards {
+			inMeld[card] = true
+		}
+	}
+
+	var meldable []Card
+	for _, card := range p.getHand() {
+		if inMeld[card] {
+			meldable = append(meldable, card)
+		}
+	}
+
+	return meldable
+}
 
-func (match *Match) PlayerOneWonTrick() bool {}
+// PlayerOneMeldItems itemizes the melds playerOne has made this game.
+func (match *Match) PlayerOneMeldItems() []MeldItem {
+	return match.playerOne.meldItems
+}
 
-func (match *Match) DoneMelding() bool {}
+// PlayerTwoMeldItems itemizes the melds playerTwo has made this game.
+func (match *Match) PlayerTwoMeldItems() []MeldItem {
+	return match.playerTwo.meldItems
+}
 
-func (match *Match) PlayerOneMeldableCards() []Card {}
+// AssignTrickPoints credits the winner of the most recently decided trick with the
+// counters it contains, plus the last trick bonus once both hands are empty, which
+// also finishes the game and scores it. The captured cards are kept with the
+// winner. An error is returned if the trick has not been decided or its points
+// were already assigned.
+func (match *Match) AssignTrickPoints() error {
+	if !match.trickUnassigned {
+		return errors.New("no decided trick is waiting for its points")
+	}
 
-func (match *Match) PlayerTwoMeldableCards() []Card {}
+	_, points := match.lastTrick.Resolve()
+	trickPhase, _ := match.TrickPhase()
+	if !trickPhase && !match.playerOne.hasCards() && !match.playerTwo.hasCards() {
+		points += match.pointValues.lastTrick
+	}
 
-func (match *Match) PlayerOneMeld(attempt []Card) bool {}
+	if match.playerOneWonTrick {
+		match.playerOne.scoreTrickPoints(points)
+		match.playerOneCaptured = append(match.playerOneCaptured, match.mostRecentlyPlayed[:]...)
+	} else {
+		match.playerTwo.scoreTrickPoints(points)
+		match.playerTwoCaptured = append(match.playerTwoCaptured, match.mostRecentlyPlayed[:]...)
+	}
 
-func (match *Match) PlayerTwoMeld(attempt []Card) bool {}
+	match.trickUnassigned = false
+	if !trickPhase && !match.playerOne.hasCards() && !match.playerTwo.hasCards() {
+		match.scoreGame()
+	}
 
-func (match *Match) MeldWasSuccesful() bool {}
+	return nil
+}
 
-func (match *Match) PlayerOneWoneGame() bool {}
+// PlayerOneWonTrick returns whether or not playerOne won the most recently decided trick.
+func (match *Match) PlayerOneWonTrick() bool {
+	return match.playerOneWonTrick
+}
 
-func (match *Match) PlayerOneWonMatch() bool {}
+// PlayerOneCapturedCards returns the cards playerOne has won in tricks this game.
+func (match *Match) PlayerOneCapturedCards() []Card {
+	return match.playerOneCaptured
+}
 
-*/
+// PlayerTwoCapturedCards returns the cards playerTwo has won in tricks this game.
+func (match *Match) PlayerTwoCapturedCards() []Card {
+	return match.playerTwoCaptured
+}
 
 func initializePoints() points {
-	pointValues := points{
-		ace:   11,
-		ten:   10,
-		king:  4,
-		queen: 3,
-		jack:  2,
-		classA: classA{
-			flush:         150,
-			royalMarriage: 40,
-			marriage:      20,
-			dix:           10,
-		},
-		classB: classB{
-			hundredAces: 100,
-			eightyKings: 80,
-			sixtyQueens: 60,
-			fortyJacks:  40,
-		},
-		classC: classC{
-			pinochle:       40,
-			doublePinochle: 300,
-		},
-	}
-
-	return pointValues
+	return ClassicRules.points()
 }
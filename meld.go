@@ -0,0 +1,247 @@
+package pinochle
+
+// MeldItem is a single scored meld.
+type MeldItem struct {
+	Name   string
+	Cards  []Card
+	Points int
+}
+
+// MeldScore itemizes the melds found in a set of cards.
+type MeldScore struct {
+	Items []MeldItem
+	Total int
+}
+
+// EvaluateMeld finds the most valuable set of melds that can be declared at
+// once from cards when trump is the turned-up trump card, with the standard
+// meld values. A card may serve in melds of different classes, but only once
+// within a class, so a king can sit in both a marriage and kings around, while
+// a run does not also count its royal marriage.
+func EvaluateMeld(cards []Card, trump Card) MeldScore {
+	return bestMelds(cards, nil, trump.suit, initializePoints())
+}
+
+func (meld valuedMeld) item() *MeldItem {
+	return &MeldItem{meld.name, append([]Card(nil), meld.cards...), meld.points}
+}
+
+// meldUsage counts, per class, how many copies of each card already sit in a
+// meld. A card may be melded again in another class, but not in the same one,
+// and every new meld must bring at least one card that hasn't been melded yet.
+type meldUsage map[meldClass]map[Card]int
+
+// newMeldUsage classifies previous against melds and caps every count at the
+// copies still in hand, since a melded card that was played is gone.
+func newMeldUsage(hand []Card, previous [][]Card, melds []valuedMeld) meldUsage {
+	inHand := make(map[Card]int)
+	for _, card := range hand {
+		inHand[card]++
+	}
+
+	usage := meldUsage{meldClassA: {}, meldClassB: {}, meldClassC: {}}
+	for _, cards := range previous {
+		for _, meld := range melds {
+			if compareCardSlices(cards, meld.cards) {
+				for _, card := range cards {
+					usage[meld.class][card]++
+				}
+				break
+			}
+		}
+	}
+
+	for _, used := range usage {
+		for card, count := range used {
+			if count > inHand[card] {
+				used[card] = inHand[card]
+			}
+		}
+	}
+
+	return usage
+}
+
+// exposed returns how many copies of card have been melded in any class.
+func (usage meldUsage) exposed(card Card) int {
+	most := 0
+	for _, used := range usage {
+		if used[card] > most {
+			most = used[card]
+		}
+	}
+
+	return most
+}
+
+// allows reports whether meld may be made from hand under the reuse rules.
+func (usage meldUsage) allows(hand []Card, meld valuedMeld) bool {
+	inHand := make(map[Card]int)
+	for _, card := range hand {
+		inHand[card]++
+	}
+
+	needed := make(map[Card]int)
+	for _, card := range meld.cards {
+		needed[card]++
+	}
+
+	newCard := false
+	for card, count := range needed {
+		if inHand[card]-usage[meld.class][card] < count {
+			return false
+		}
+
+		if usage[meld.class][card]+count > usage.exposed(card) {
+			newCard = true
+		}
+	}
+
+	return newCard
+}
+
+// legalMelds returns every meld that can be made from hand when trumpSuit is
+// trump, given the melds already made from it. A royal marriage may be built up
+// into a run by adding the ace, ten and jack of trump, which score the full run.
+func legalMelds(hand []Card, previous [][]Card, trumpSuit string, values points) []valuedMeld {
+	slices := newMeldSlices(trumpSuit)
+	melds := slices.withPoints(values)
+	usage := newMeldUsage(hand, previous, melds)
+
+	var marriages, rest [][]Card
+	for _, cards := range previous {
+		if compareCardSlices(cards, slices.royalMarriage) {
+			marriages = append(marriages, cards)
+		} else {
+			rest = append(rest, cards)
+		}
+	}
+
+	runUsage := usage
+	if len(marriages) > 0 {
+		runUsage = newMeldUsage(hand, rest, melds)
+	}
+
+	var legal []valuedMeld
+	for _, meld := range melds {
+		allowed := usage.allows(hand, meld)
+		if isRun(meld, slices) {
+			allowed = runUsage.allows(hand, meld)
+		}
+
+		if meld.points > 0 && allowed {
+			legal = append(legal, meld)
+		}
+	}
+
+	return legal
+}
+
+// isRun reports whether meld is a run, single or repeated, in trump.
+func isRun(meld valuedMeld, slices validMeldSlices) bool {
+	for _, run := range [][]Card{slices.flush, slices.doubleRun, slices.tripleRun, slices.quadrupleRun} {
+		if compareCardSlices(meld.cards, run) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shownCards returns the cards of hand that lie melded on the table, and so are
+// known to the opponent.
+func shownCards(hand []Card, previous [][]Card, trumpSuit string, values points) []Card {
+	slices := newMeldSlices(trumpSuit)
+	usage := newMeldUsage(hand, previous, slices.withPoints(values))
+
+	var shown []Card
+	counted := make(map[Card]bool)
+	for _, card := range hand {
+		if counted[card] {
+			continue
+		}
+
+		counted[card] = true
+		for i := 0; i < usage.exposed(card); i++ {
+			shown = append(shown, card)
+		}
+	}
+
+	return shown
+}
+
+// bestMelds finds the most valuable set of melds that can be declared at once
+// from hand on top of the melds already made from it. Classes don't share
+// cards, so each is settled on its own.
+func bestMelds(hand []Card, previous [][]Card, trumpSuit string, values points) MeldScore {
+	candidates := legalMelds(hand, previous, trumpSuit, values)
+	slices := newMeldSlices(trumpSuit)
+	usage := newMeldUsage(hand, previous, slices.withPoints(values))
+
+	var score MeldScore
+	for _, class := range []meldClass{meldClassA, meldClassB, meldClassC} {
+		available := make(map[Card]int)
+		for _, card := range hand {
+			available[card]++
+		}
+
+		for card, used := range usage[class] {
+			available[card] -= used
+		}
+
+		var inClass []valuedMeld
+		for _, meld := range candidates {
+			if meld.class == class {
+				inClass = append(inClass, meld)
+			}
+		}
+
+		total, melds := bestInClass(inClass, available)
+		score.Total += total
+		for _, meld := range melds {
+			score.Items = append(score.Items, *meld.item())
+		}
+	}
+
+	return score
+}
+
+// bestInClass picks the most valuable combination of melds, each possibly more
+// than once, that available can cover.
+func bestInClass(melds []valuedMeld, available map[Card]int) (int, []valuedMeld) {
+	best, bestMelds := 0, []valuedMeld(nil)
+	for i, meld := range melds {
+		if !coverable(meld.cards, available) {
+			continue
+		}
+
+		for _, card := range meld.cards {
+			available[card]--
+		}
+
+		total, rest := bestInClass(melds[i:], available)
+		for _, card := range meld.cards {
+			available[card]++
+		}
+
+		if total+meld.points > best {
+			best = total + meld.points
+			bestMelds = append([]valuedMeld{meld}, rest...)
+		}
+	}
+
+	return best, bestMelds
+}
+
+// coverable reports whether available holds every card of cards.
+func coverable(cards []Card, available map[Card]int) bool {
+	needed := make(map[Card]int)
+	for _, card := range cards {
+		needed[card]++
+		if needed[card] > available[card] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,288 @@
+package pinochle
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Strength selects how much searching a Computer does before each play.
+type Strength int
+
+const (
+	// Easy plays by heuristics alone.
+	Easy Strength = iota
+	// Medium samples a few hundred deals for every play.
+	Medium
+	// Hard samples a few thousand deals, for at most a second per play.
+	Hard
+)
+
+// NewComputer returns a Computer playing at strength.
+func NewComputer(strength Strength) *Computer {
+	switch strength {
+	case Medium:
+		return &Computer{Iterations: 300}
+	case Hard:
+		return &Computer{Iterations: 3000, Budget: time.Second}
+	}
+
+	return &Computer{}
+}
+
+// searching reports whether the Computer uses Monte Carlo search.
+func (c *Computer) searching() bool {
+	return c.Iterations > 0 || c.Budget > 0
+}
+
+func (c *Computer) random() *rand.Rand {
+	if c.rng == nil {
+		seed := c.Seed
+		if seed == 0 {
+			seed = time.Now().UTC().UnixNano()
+		}
+		c.rng = rand.New(rand.NewSource(seed))
+	}
+
+	return c.rng
+}
+
+// search chooses a card by determinized Monte Carlo search. Every iteration
+// deals the unseen cards at random, consistently with what table shows, picks a
+// candidate by UCB1 and plays the rest of the game out with the heuristics. The
+// candidate with the best average outcome is played.
+func (c *Computer) search(table Table) Card {
+	var candidates []Card
+	for _, card := range table.Legal {
+		if !containsCards(candidates, []Card{card}) {
+			candidates = append(candidates, card)
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	visits := make([]int, len(candidates))
+	rewards := make([]float64, len(candidates))
+	start := time.Now()
+	for i := 0; ; i++ {
+		if c.Iterations > 0 && i >= c.Iterations {
+			break
+		}
+
+		if c.Budget > 0 && time.Since(start) >= c.Budget {
+			break
+		}
+
+		pick := ucb(visits, rewards, i)
+		game := determinize(table, c.random())
+		game.play(0, candidates[pick])
+		game.rollout(c)
+
+		// Map the point margin from here on onto [0, 1].
+		margin := float64(game.points[0]-game.points[1]) / 500
+		visits[pick]++
+		rewards[pick] += math.Max(0, math.Min(1, 0.5+margin))
+	}
+
+	best := 0
+	for i := range candidates {
+		if visits[i] > 0 && (visits[best] == 0 || rewards[i]/float64(visits[i]) > rewards[best]/float64(visits[best])) {
+			best = i
+		}
+	}
+
+	return candidates[best]
+}
+
+// ucb returns the index with the highest UCB1 score, trying each once first.
+func ucb(visits []int, rewards []float64, total int) int {
+	best, bestScore := 0, math.Inf(-1)
+	for i := range visits {
+		if visits[i] == 0 {
+			return i
+		}
+
+		n := float64(visits[i])
+		score := rewards[i]/n + math.Sqrt(2*math.Log(float64(total))/n)
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	return best
+}
+
+// simGame is a lightweight two-handed game used to play deals out. Seat 0 is
+// the searching player.
+type simGame struct {
+	hands  [2][]Card
+	melds  [2][][]Card
+	stock  []Card
+	trump  Card
+	leader int
+	led    Card
+	points [2]int
+	values points
+}
+
+// determinize deals the cards table doesn't show at random: the opponent keeps
+// its melded cards and is dealt the rest of its hand, and the stock gets the rest.
+func determinize(table Table, rng *rand.Rand) *simGame {
+	unseen := make(map[Card]int)
+	for _, card := range buildDeck(nil).stack {
+		unseen[card]++
+	}
+
+	known := [][]Card{table.Hand, table.Seen, table.OpponentShown}
+	if table.StockSize > 0 {
+		known = append(known, []Card{table.Trump})
+	}
+
+	for _, cards := range known {
+		for _, card := range cards {
+			if unseen[card] > 0 {
+				unseen[card]--
+			}
+		}
+	}
+
+	var pool []Card
+	for _, card := range buildDeck(nil).stack {
+		if unseen[card] > 0 {
+			unseen[card]--
+			pool = append(pool, card)
+		}
+	}
+
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	opponent := append([]Card(nil), table.OpponentShown...)
+	for len(opponent) < table.OpponentHandSize && len(pool) > 0 {
+		opponent = append(opponent, pool[0])
+		pool = pool[1:]
+	}
+
+	if len(pool) > table.StockSize {
+		pool = pool[:table.StockSize]
+	}
+
+	game := &simGame{
+		hands:  [2][]Card{append([]Card(nil), table.Hand...), opponent},
+		melds:  [2][][]Card{append([][]Card(nil), table.Melds...), append([][]Card(nil), table.OpponentMelds...)},
+		stock:  pool,
+		trump:  table.Trump,
+		led:    DummyCard,
+		values: table.values(),
+	}
+
+	if !CompareCards(table.Led, DummyCard) {
+		game.leader = 1
+		game.led = table.Led
+	}
+
+	return game
+}
+
+// turn returns the seat that plays next.
+func (g *simGame) turn() int {
+	if CompareCards(g.led, DummyCard) {
+		return g.leader
+	}
+
+	return 1 - g.leader
+}
+
+func (g *simGame) table(idx int) Table {
+	hand := g.hands[idx]
+	legal := hand
+	if !CompareCards(g.led, DummyCard) && len(g.stock) == 0 {
+		legal = nil
+		for _, card := range hand {
+			if playoffViolation(hand, g.led, card, g.trump.suit) == nil {
+				legal = append(legal, card)
+			}
+		}
+	}
+
+	return Table{
+		Hand:        hand,
+		Legal:       legal,
+		Led:         g.led,
+		Trump:       g.trump,
+		StockSize:   len(g.stock),
+		Melds:       g.melds[idx],
+		pointValues: g.values,
+	}
+}
+
+// play plays card for the seat idx, resolving the trick, the winner's meld and
+// the draws once both cards are down.
+func (g *simGame) play(idx int, card Card) {
+	for i, c := range g.hands[idx] {
+		if CompareCards(c, card) {
+			g.hands[idx] = append(g.hands[idx][:i:i], g.hands[idx][i+1:]...)
+			break
+		}
+	}
+
+	if CompareCards(g.led, DummyCard) {
+		g.leader = idx
+		g.led = card
+		return
+	}
+
+	winner := g.leader
+	if beats(card, g.led, g.trump.suit) {
+		winner = idx
+	}
+
+	points := g.values.cardPoints(g.led) + g.values.cardPoints(card)
+	if len(g.stock) == 0 && len(g.hands[0]) == 0 && len(g.hands[1]) == 0 {
+		points += g.values.lastTrick
+	}
+
+	g.points[winner] += points
+	g.leader = winner
+	g.led = DummyCard
+	if len(g.stock) == 0 {
+		return
+	}
+
+	var best valuedMeld
+	for _, meld := range legalMelds(g.hands[winner], g.melds[winner], g.trump.suit, g.values) {
+		if meld.points > best.points {
+			best = meld
+		}
+	}
+
+	if best.points > 0 {
+		g.melds[winner] = append(g.melds[winner], best.cards)
+		g.points[winner] += best.points
+	}
+
+	top := len(g.stock) - 1
+	g.hands[winner] = append(g.hands[winner], g.stock[top])
+	if top == 0 {
+		g.hands[1-winner] = append(g.hands[1-winner], g.trump)
+		g.stock = nil
+		return
+	}
+
+	g.hands[1-winner] = append(g.hands[1-winner], g.stock[top-1])
+	g.stock = g.stock[:top-1]
+}
+
+// rollout plays the game out with the heuristics of c.
+func (g *simGame) rollout(c *Computer) {
+	for len(g.hands[0]) > 0 || len(g.hands[1]) > 0 {
+		idx := g.turn()
+		table := g.table(idx)
+		if len(table.Legal) == 0 {
+			return
+		}
+
+		g.play(idx, c.playHeuristic(table))
+	}
+}
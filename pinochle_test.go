@@ -1,6 +1,10 @@
 package pinochle
 
 import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -28,23 +32,23 @@ func TestHandContains(t *testing.T) {
 	playerOne := Human{}
 	playerTwo := Computer{}
 
-	m := InitializeMatch(&playerOne, &playerTwo, 1000)
+	m := InitializeMatch(&playerOne, &playerTwo, ClassicRules)
 	m.dealerPlayerOne = true // assure that deal will be accurate
 	m.NewGame(false)
 
-	if !m.handContains(m.PlayerTwoHand(), Card{"9", "H"}) {
+	if ok, _ := m.playerTwo.handContains(Card{"9", "H"}); !ok {
 		t.Errorf("%v contains %v", m.PlayerTwoHand(), Card{"9", "H"})
 	}
 
-	if !m.handContains(m.PlayerTwoHand(), Card{"J", "C"}) {
+	if ok, _ := m.playerTwo.handContains(Card{"J", "C"}); !ok {
 		t.Errorf("%v contains %v", m.PlayerTwoHand(), Card{"J", "C"})
 	}
 
-	if m.handContains(m.PlayerTwoHand(), Card{"Q", "H"}) {
+	if ok, _ := m.playerTwo.handContains(Card{"Q", "H"}); ok {
 		t.Errorf("%v does not contain %v, but playerOne does.", m.PlayerTwoHand(), Card{"Q", "H"})
 	}
 
-	if m.handContains(m.PlayerTwoHand(), Card{"K", "D"}) {
+	if ok, _ := m.playerTwo.handContains(Card{"K", "D"}); ok {
 		t.Errorf("%v does not contain %v", m.PlayerTwoHand(), Card{"K", "D"})
 	}
 }
@@ -53,9 +57,9 @@ func TestBuildDeckShuffle(t *testing.T) {
 	playerOne := Human{}
 	playerTwo := Computer{}
 
-	shuffled := InitializeMatch(&playerOne, &playerTwo, 100)
-	shuffled.NewGame(true)
-	notShuffled := InitializeMatch(&playerOne, &playerTwo, 100)
+	shuffled := InitializeMatch(&playerOne, &playerTwo, playingTo(100))
+	shuffled.NewGameWithSeed(1)
+	notShuffled := InitializeMatch(&playerOne, &playerTwo, playingTo(100))
 	notShuffled.NewGame(false)
 
 	samePositionCount := 0
@@ -70,11 +74,57 @@ func TestBuildDeckShuffle(t *testing.T) {
 	}
 }
 
+func TestSeededDeal(t *testing.T) {
+	first := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	second := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	first.NewGame(true)
+	second.NewGameWithSeed(first.Seed())
+	if !compareCardSlices(first.PlayerOneHand(), second.PlayerOneHand()) || first.deck.trump != second.deck.trump {
+		t.Errorf("seed %v should replay the deal: %v, %v", first.Seed(), first.PlayerOneHand(), second.PlayerOneHand())
+	}
+
+	for i := range first.deck.stack {
+		if !CompareCards(first.deck.stack[i], second.deck.stack[i]) {
+			t.Fatalf("seed %v should replay the stack: %v, %v", first.Seed(), first.deck.stack, second.deck.stack)
+		}
+	}
+
+	// Seeding the match replays every game of it.
+	first = InitializeMatch(&Computer{}, &Computer{}, ClassicRules)
+	second = InitializeMatch(&Computer{}, &Computer{}, ClassicRules)
+	first.SetSeed(7)
+	second.SetSeed(7)
+	firstResult, _ := first.Run(true)
+	secondResult, _ := second.Run(true)
+	if firstResult != secondResult || first.Seed() != second.Seed() {
+		t.Errorf("seeded matches should be replayed exactly: %+v, %+v", firstResult, secondResult)
+	}
+
+	// A custom Shuffler decides the deal.
+	reverse := reverser{}
+	first.SetShuffler(&reverse)
+	first.NewGame(true)
+	if !reverse.called || first.Seed() != 0 {
+		t.Error("NewGame didn't use the Shuffler it was given")
+	}
+}
+
+type reverser struct {
+	called bool
+}
+
+func (r *reverser) Shuffle(stack []Card) {
+	r.called = true
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+}
+
 func TestDeal(t *testing.T) {
 	playerOne := Human{}
 	playerTwo := Computer{}
 
-	match := InitializeMatch(&playerOne, &playerTwo, 100)
+	match := InitializeMatch(&playerOne, &playerTwo, playingTo(100))
 
 	match.NewGame(true)
 	if !(len(match.PlayerOneHand()) == 12 && len(match.PlayerTwoHand()) == 12) {
@@ -92,7 +142,7 @@ func TestDeal(t *testing.T) {
 
 func TestBuildDeckGeneration(t *testing.T) {
 
-	d := buildDeck(false)
+	d := buildDeck(nil)
 	if len(d.stack) != 48 {
 		t.Errorf("deck.stack is the wrong length: %v", d.stack)
 	}
@@ -110,7 +160,7 @@ func TestBuildDeckGeneration(t *testing.T) {
 		}
 	}
 
-	d = buildDeck(true)
+	d = buildDeck(NewSeededShuffler(1))
 	if len(d.stack) != 48 {
 		t.Errorf("deck.stack is the wrong length: %v", d.stack)
 	}
@@ -131,8 +181,8 @@ func TestBuildDeckGeneration(t *testing.T) {
 
 func TestDealingCards(t *testing.T) {
 	playerOne := Human{}
-	playerTwo := Computer{}
-	match := InitializeMatch(&playerOne, &playerTwo, 100)
+	playerTwo := Human{}
+	match := InitializeMatch(&playerOne, &playerTwo, playingTo(100))
 
 	// capture dealerPlayerOne == false
 	for i := 0; i < 2; i++ {
@@ -150,7 +200,7 @@ func TestDealingCards(t *testing.T) {
 		for trickPhase, lastCard := match.TrickPhase(); trickPhase; trickPhase, lastCard = match.TrickPhase() {
 			// In an actual game, here you'd base which played first on match.PlayerOneTurn()
 			errPlayPlayerOne := match.PlayerOnePlayed(match.playerOne.getHand()[0])
-			errPlayPlayerTwo := match.PlayerTwoPlayed(DummyCard)
+			errPlayPlayerTwo := match.PlayerTwoPlayed(match.playerTwo.getHand()[0])
 			// ------------------------------------------------------------------------------
 			if errPlayPlayerOne != nil {
 				t.Error("playerOne got an error while playing a card (trick phase) : " +
@@ -185,15 +235,16 @@ func TestDealingCards(t *testing.T) {
 
 		for match.Playoff() {
 			// Base of off match.PlayerOneWonTrick()
-			errPlayPlayerOne := match.PlayerOnePlayed(match.playerOne.getHand()[0])
-			errPlayPlayerTwo := match.PlayerTwoPlayed(DummyCard)
+			errPlayPlayerOne := match.PlayerOnePlayed(match.PlayerOneLegalPlays()[0])
+			errPlayPlayerTwo := match.PlayerTwoPlayed(match.PlayerTwoLegalPlays()[0])
 			// -------------------------------------
 			if errPlayPlayerOne != nil {
 				t.Error("playerOne got an error while playing a card (playoff) : " +
 					errPlayPlayerOne.Error())
 			}
 			if errPlayPlayerTwo != nil {
-				t.Error("playerTwo got an error while playing a card (playoff)")
+				t.Error("playerTwo got an error while playing a card (playoff) : " +
+					errPlayPlayerTwo.Error())
 			}
 		}
 
@@ -223,14 +274,17 @@ func TestDecideTrickWinner(t *testing.T) {
 	pOne := Human{}
 	pTwo := Computer{}
 
-	match := InitializeMatch(&pOne, &pTwo, 100)
+	match := InitializeMatch(&pOne, &pTwo, playingTo(100))
 	match.NewGame(true)
+	play := func(one, two Card) {
+		match.mostRecentlyPlayed = [2]Card{one, two}
+		match.inTrick, match.trickDecided = [2]bool{true, true}, false
+	}
 
 	// test 1 -----------------------------------------
 	match.deck.trump = Card{"K", "S"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"9", "S"})
-	match.storePlayerTwoCard(Card{"A", "H"})
+	play(Card{"9", "S"}, Card{"A", "H"})
 	match.DecideTrickWinner()
 	if !match.playerOneWonTrick {
 		t.Errorf("playerOne lost trick, but should've won: %v", match.mostRecentlyPlayed)
@@ -244,8 +298,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 2 -----------------------------------------
 	match.deck.trump = Card{"K", "S"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"9", "S"})
-	match.storePlayerTwoCard(Card{"9", "S"})
+	play(Card{"9", "S"}, Card{"9", "S"})
 	match.DecideTrickWinner()
 	if !match.playerOneWonTrick {
 		t.Errorf("playerOne lost trick, but should've won: %v", match.mostRecentlyPlayed)
@@ -259,8 +312,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 3 -----------------------------------------
 	match.deck.trump = Card{"9", "H"}
 	match.playerOneLed = false
-	match.storePlayerOneCard(Card{"9", "H"})
-	match.storePlayerTwoCard(Card{"10", "H"})
+	play(Card{"9", "H"}, Card{"10", "H"})
 	match.DecideTrickWinner()
 	if match.playerOneWonTrick {
 		t.Errorf("playerOne won trick, but shouldn't have: %v", match.mostRecentlyPlayed)
@@ -274,8 +326,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 4 -------------------------------------------
 	match.deck.trump = Card{"A", "S"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"10", "H"})
-	match.storePlayerTwoCard(Card{"9", "C"})
+	play(Card{"10", "H"}, Card{"9", "C"})
 	match.DecideTrickWinner()
 	if !match.playerOneWonTrick {
 		t.Errorf("playerOne didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -289,8 +340,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 5 -------------------------------------------
 	match.deck.trump = Card{"A", "S"}
 	match.playerOneLed = false
-	match.storePlayerOneCard(Card{"10", "H"})
-	match.storePlayerTwoCard(Card{"9", "C"})
+	play(Card{"10", "H"}, Card{"9", "C"})
 	match.DecideTrickWinner()
 	if match.playerOneWonTrick {
 		t.Errorf("playerOne didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -304,8 +354,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 6 -------------------------------------------
 	match.deck.trump = Card{"10", "H"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"A", "H"})
-	match.storePlayerTwoCard(Card{"A", "H"})
+	play(Card{"A", "H"}, Card{"A", "H"})
 	match.DecideTrickWinner()
 	if !match.playerOneWonTrick {
 		t.Errorf("playerOne didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -319,8 +368,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 7 ------------------------------------------
 	match.deck.trump = Card{"10", "H"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"A", "C"})
-	match.storePlayerTwoCard(Card{"A", "H"})
+	play(Card{"A", "C"}, Card{"A", "H"})
 	match.DecideTrickWinner()
 	if match.playerOneWonTrick {
 		t.Errorf("playerTwo didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -334,8 +382,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 8 ------------------------------------------
 	match.deck.trump = Card{"10", "H"}
 	match.playerOneLed = true
-	match.storePlayerOneCard(Card{"Q", "C"})
-	match.storePlayerTwoCard(Card{"A", "C"})
+	play(Card{"Q", "C"}, Card{"A", "C"})
 	match.DecideTrickWinner()
 	if match.playerOneWonTrick {
 		t.Errorf("playerTwo didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -349,8 +396,7 @@ func TestDecideTrickWinner(t *testing.T) {
 	// test 9 ------------------------------------------
 	match.deck.trump = Card{"10", "H"}
 	match.playerOneLed = false
-	match.storePlayerOneCard(Card{"K", "C"})
-	match.storePlayerTwoCard(Card{"Q", "C"})
+	play(Card{"K", "C"}, Card{"Q", "C"})
 	match.DecideTrickWinner()
 	if !match.playerOneWonTrick {
 		t.Errorf("playerOne didn't win trick, but should have: %v", match.mostRecentlyPlayed)
@@ -400,10 +446,10 @@ func TestCompareCardSlices(t *testing.T) {
 	}
 }
 
-func TestValidateMeld(t *testing.T) {
+func TestLegalMelds(t *testing.T) {
 	var playerOne = &Human{}
 	var playerTwo = &Computer{}
-	m := InitializeMatch(playerOne, playerTwo, 1000)
+	m := InitializeMatch(playerOne, playerTwo, ClassicRules)
 	m.dealerPlayerOne = true
 	m.NewGame(false)
 	tmp := Card{"9", "D"}
@@ -411,33 +457,1423 @@ func TestValidateMeld(t *testing.T) {
 		t.Errorf("invariant broken: m.deck.trump != Card{\"9\", \"D\"}, %s", m.deck.trump)
 	}
 
+	// meldPoints looks for meld among those legal from a hand of just its cards.
+	meldPoints := func(meld []Card, previous [][]Card) (int, bool) {
+		for _, legal := range legalMelds(meld, previous, m.deck.trump.suit, m.pointValues) {
+			if compareCardSlices(legal.cards, meld) {
+				return legal.points, true
+			}
+		}
+
+		return 0, false
+	}
+
 	meld := []Card{Card{"J", "D"}, Card{"Q", "S"}}
-	point, err := m.validateMeld(meld)
-	if err != nil || point != 40 {
-		t.Errorf("pinochle is a valid meld for 40 points: %v, %v", err, point)
+	if point, ok := meldPoints(meld, nil); !ok || point != 40 {
+		t.Errorf("pinochle is a valid meld for 40 points: %v, %v", ok, point)
 	}
 
 	meld = []Card{Card{"K", "D"}, Card{"Q", "D"}}
-	point, err = m.validateMeld(meld)
-	if err != nil || point != 40 {
-		t.Errorf("royal marriage is a valid meld for 40 points: %v, %v", err, point)
+	if point, ok := meldPoints(meld, nil); !ok || point != 40 {
+		t.Errorf("royal marriage is a valid meld for 40 points: %v, %v", ok, point)
+	}
+
+	if _, ok := meldPoints(meld, [][]Card{meld}); ok {
+		t.Errorf("%v was melded again from the same cards", meld)
 	}
 
 	meld = []Card{Card{"A", "D"}, Card{"10", "D"}, Card{"K", "D"}, Card{"Q", "D"}, Card{"J", "D"}}
-	point, err = m.validateMeld(meld)
-	if err != nil || point != 150 {
-		t.Errorf("flush is a valid meld for 150 points: %v, %v", err, point)
+	if point, ok := meldPoints(meld, nil); !ok || point != 150 {
+		t.Errorf("flush is a valid meld for 150 points: %v, %v", ok, point)
 	}
 
 	meld = []Card{Card{"9", "D"}}
-	point, err = m.validateMeld(meld)
-	if err != nil || point != 10 {
-		t.Errorf("dix is a valid meld for 10 points: %v, %v", err, point)
+	if point, ok := meldPoints(meld, nil); !ok || point != 10 {
+		t.Errorf("dix is a valid meld for 10 points: %v, %v", ok, point)
 	}
 
 	meld = []Card{Card{"K", "S"}, Card{"Q", "H"}}
-	point, err = m.validateMeld(meld)
-	if err == nil {
+	if _, ok := meldPoints(meld, nil); ok {
 		t.Errorf("%v is not a valid meld", meld)
 	}
 }
+
+func TestMeld(t *testing.T) {
+	playerOne := Human{}
+	playerTwo := Computer{}
+	m := InitializeMatch(&playerOne, &playerTwo, ClassicRules)
+	m.dealerPlayerOne = true
+	m.NewGame(false)
+
+	m.playerOne.hand = []Card{Card{"A", "S"}, Card{"K", "D"}, Card{"Q", "D"}, Card{"J", "D"}, Card{"Q", "S"}}
+	m.playerTwo.hand = []Card{Card{"K", "C"}, Card{"Q", "C"}, Card{"9", "C"}}
+
+	if m.PlayerOneMeld([]Card{Card{"K", "D"}, Card{"Q", "D"}}) {
+		t.Error("playerOne melded before winning a trick")
+	}
+
+	m.playerOneLed = true
+	m.PlayerOnePlayed(Card{"A", "S"})
+	m.PlayerTwoPlayed(Card{"9", "C"})
+	if err := m.DecideTrickWinner(); err != nil {
+		t.Fatal(err)
+	}
+
+	if meldable := m.PlayerOneMeldableCards(); !compareCardSlices(meldable, m.playerOne.hand) {
+		t.Errorf("every card in %v is meldable, got %v", m.playerOne.hand, meldable)
+	}
+
+	if m.PlayerTwoMeld([]Card{Card{"K", "C"}, Card{"Q", "C"}}) {
+		t.Error("playerTwo melded after losing the trick")
+	}
+
+	if m.PlayerOneMeld([]Card{Card{"K", "S"}, Card{"Q", "S"}}) || m.MeldWasSuccessful() {
+		t.Error("playerOne melded cards that aren't in hand")
+	}
+
+	if !m.PlayerOneMeld([]Card{Card{"K", "D"}, Card{"Q", "D"}}) || !m.MeldWasSuccessful() {
+		t.Error("playerOne should be able to meld a royal marriage")
+	}
+
+	if m.playerOne.meldScore() != 40 {
+		t.Errorf("royal marriage should score 40, got %v", m.playerOne.meldScore())
+	}
+
+	if m.PlayerOneMeld([]Card{Card{"J", "D"}, Card{"Q", "S"}}) {
+		t.Error("playerOne melded twice in one trick")
+	}
+
+	if !m.DoneMelding() {
+		t.Error("DoneMelding should report that playerOne melded")
+	}
+
+	if len(m.PlayerOneHand()) != 4 || len(m.PlayerOneMelds()) != 1 {
+		t.Errorf("melded cards should stay in hand: %v, melds: %v", m.PlayerOneHand(), m.PlayerOneMelds())
+	}
+}
+
+func TestAssignTrickPoints(t *testing.T) {
+	playerOne := Human{}
+	playerTwo := Computer{}
+	m := InitializeMatch(&playerOne, &playerTwo, ClassicRules)
+	m.NewGame(false)
+
+	if err := m.AssignTrickPoints(); err == nil {
+		t.Error("points were assigned before a trick was decided")
+	}
+
+	m.deck.trump = Card{"9", "H"}
+	m.playerOneLed = true
+	m.playerOne.hand = []Card{Card{"A", "S"}, Card{"J", "S"}}
+	m.playerTwo.hand = []Card{Card{"K", "C"}, Card{"10", "S"}}
+	m.PlayerOnePlayed(Card{"A", "S"})
+	m.PlayerTwoPlayed(Card{"10", "S"})
+	m.DecideTrickWinner()
+	if err := m.AssignTrickPoints(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.PlayerOneWonTrick() || m.playerOne.currentTrickScore != 21 {
+		t.Errorf("playerOne should have taken %v for 21 points, got %v", m.mostRecentlyPlayed, m.playerOne.currentTrickScore)
+	}
+
+	if err := m.AssignTrickPoints(); err == nil {
+		t.Error("the same trick was assigned twice")
+	}
+
+	if err := m.DecideTrickWinner(); err == nil || m.AssignTrickPoints() == nil {
+		t.Error("the same trick was decided twice")
+	}
+
+	m.PlayerOnePlayed(Card{"J", "S"})
+	if err := m.DecideTrickWinner(); err == nil || m.AssignTrickPoints() == nil {
+		t.Error("a trick was decided with only one card played")
+	}
+
+	if m.playerOne.currentTrickScore != 21 || len(m.PlayerOneCapturedCards()) != 2 {
+		t.Errorf("the first trick was scored again: %v points, captured %v", m.playerOne.currentTrickScore, m.PlayerOneCapturedCards())
+	}
+
+	// The last trick of the game carries a bonus.
+	m.deck.stack = nil
+	m.PlayerTwoPlayed(Card{"K", "C"})
+	m.DecideTrickWinner()
+	m.AssignTrickPoints()
+	if m.ScoreHistory()[0].PlayerOneTricks != 21+2+4+10 || m.PlayerOneScore() != 37 {
+		t.Errorf("playerOne should have 37 trick points, got %+v", m.ScoreHistory())
+	}
+
+	if len(m.PlayerOneCapturedCards()) != 4 || len(m.PlayerTwoCapturedCards()) != 0 {
+		t.Errorf("captured cards are wrong: %v, %v", m.PlayerOneCapturedCards(), m.PlayerTwoCapturedCards())
+	}
+}
+
+func TestPlayoffRules(t *testing.T) {
+	playerOne := Human{}
+	playerTwo := Human{}
+	m := InitializeMatch(&playerOne, &playerTwo, ClassicRules)
+	m.NewGame(false)
+	m.deck.stack = nil
+	m.deck.trump = Card{"9", "H"}
+
+	m.playerOne.hand = []Card{Card{"K", "S"}, Card{"K", "C"}, Card{"J", "C"}}
+	m.playerTwo.hand = []Card{Card{"Q", "S"}, Card{"A", "S"}, Card{"9", "H"}, Card{"A", "C"}}
+	if err := m.PlayerOnePlayed(Card{"K", "S"}); err != nil {
+		t.Fatal(err)
+	}
+
+	legal := m.PlayerTwoLegalPlays()
+	if !compareCardSlices(legal, []Card{Card{"A", "S"}}) {
+		t.Errorf("only the ace of spades heads the trick, got %v", legal)
+	}
+
+	if err := m.PlayerTwoPlayed(Card{"Q", "S"}); err == nil {
+		t.Error("playerTwo should have to head the trick")
+	}
+
+	if err := m.PlayerTwoPlayed(Card{"A", "C"}); err == nil {
+		t.Error("playerTwo should have to follow suit")
+	}
+
+	if err := m.PlayerTwoPlayed(Card{"A", "S"}); err != nil {
+		t.Error(err)
+	}
+
+	// playerTwo is void in clubs, so it must trump.
+	m.playerTwo.hand = []Card{Card{"Q", "D"}, Card{"9", "H"}}
+	if err := m.PlayerOnePlayed(Card{"K", "C"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.PlayerOnePlayed(Card{"J", "C"}); err == nil {
+		t.Error("playerOne played twice to the same trick")
+	}
+
+	if err := m.PlayerTwoPlayed(Card{"Q", "D"}); err == nil {
+		t.Error("playerTwo should have to trump")
+	}
+
+	if err := m.PlayerTwoPlayed(DummyCard); err == nil {
+		t.Error("playerTwo played a card it doesn't hold")
+	}
+
+	if err := m.PlayerTwoPlayed(Card{"9", "H"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	playerOne := Computer{}
+	playerTwo := Computer{}
+	m := InitializeMatch(&playerOne, &playerTwo, playingTo(500))
+	m.SetSeed(1)
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.MatchOver() || result.Games < 2 {
+		t.Errorf("match should be over after at least two games: %+v", result)
+	}
+
+	winner, loser := result.PlayerOneScore, result.PlayerTwoScore
+	if !result.PlayerOneWon {
+		winner, loser = loser, winner
+	}
+
+	if winner < 500 || winner <= loser {
+		t.Errorf("the winner should have reached 500 with the higher score: %+v", result)
+	}
+
+	if m.playerOne.hasCards() || m.playerTwo.hasCards() || len(m.deck.stack) != 0 {
+		t.Errorf("cards left after the match: %v, %v, %v", m.playerOne.hand, m.playerTwo.hand, m.deck.stack)
+	}
+
+	human := Human{}
+	m = InitializeMatch(&human, &Computer{}, playingTo(500))
+	if _, err := m.Run(false); err == nil {
+		t.Error("a Human without a Chooser can't be run")
+	}
+
+	human = Human{
+		Chooser: func(table Table) Card { return table.Legal[0] },
+		Melder: func(table Table) []Card {
+			if containsCards(table.Hand, []Card{Card{"Q", "S"}, Card{"J", "D"}}) && len(table.Melds) == 0 {
+				return []Card{Card{"Q", "S"}, Card{"J", "D"}}
+			}
+
+			return nil
+		},
+	}
+	m = InitializeMatch(&human, &Computer{}, playingTo(500))
+	if _, err := m.Run(true); err != nil {
+		t.Error(err)
+	}
+}
+
+// firstLegal is a Player written the way an outside package would write one.
+type firstLegal struct {
+	plays int
+}
+
+func (f *firstLegal) Play(table Table) (Card, error) {
+	f.plays++
+	return table.Legal[0], nil
+}
+
+func (f *firstLegal) Meld(table Table) ([]Card, error) {
+	return nil, nil
+}
+
+func TestPluggablePlayer(t *testing.T) {
+	bot := firstLegal{}
+	m := InitializeMatch(&bot, &Computer{}, playingTo(300))
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bot.plays != 24*result.Games {
+		t.Errorf("the bot should have played 24 cards a game, played %v in %v games", bot.plays, result.Games)
+	}
+
+	if m.playerOne.score() != result.PlayerOneScore {
+		t.Errorf("the Match should keep the bot's score: %v, %+v", m.playerOne.score(), result)
+	}
+}
+
+func TestComputerPlay(t *testing.T) {
+	c := Computer{}
+	trump := Card{"9", "S"}
+
+	// A counter that can be taken with a card of the led suit is taken.
+	table := Table{Led: Card{"10", "H"}, Trump: trump, StockSize: 10}
+	table.Hand = []Card{Card{"A", "H"}, Card{"9", "C"}, Card{"K", "S"}}
+	table.Legal = table.Hand
+	if card, _ := c.Play(table); !CompareCards(card, Card{"A", "H"}) {
+		t.Errorf("Computer should take %v with the ace, played %v", table.Led, card)
+	}
+
+	// A worthless lead is ducked rather than trumped.
+	table.Led = Card{"9", "D"}
+	table.Hand = []Card{Card{"J", "C"}, Card{"K", "S"}, Card{"A", "S"}}
+	table.Legal = table.Hand
+	if card, _ := c.Play(table); !CompareCards(card, Card{"J", "C"}) {
+		t.Errorf("Computer should duck %v with the jack of clubs, played %v", table.Led, card)
+	}
+
+	// Meld combinations are held on to while there is a stock.
+	table.Led = DummyCard
+	table.Hand = []Card{Card{"K", "D"}, Card{"Q", "D"}, Card{"10", "C"}}
+	table.Legal = table.Hand
+	if card, _ := c.Play(table); !CompareCards(card, Card{"10", "C"}) {
+		t.Errorf("Computer should keep its marriage and lead the ten of clubs, played %v", card)
+	}
+
+	// In the playoff only legal cards are played, and the trick is taken if possible.
+	playerOne := Human{}
+	m := InitializeMatch(&playerOne, &c, ClassicRules)
+	m.NewGame(false)
+	m.deck.stack = nil
+	m.deck.trump = trump
+	m.playerOne.hand = []Card{Card{"Q", "H"}}
+	m.playerTwo.hand = []Card{Card{"9", "H"}, Card{"K", "H"}, Card{"A", "S"}, Card{"J", "D"}}
+	m.PlayerOnePlayed(Card{"Q", "H"})
+	if err := m.PlayerTwoPlayed(DummyCard); err != nil {
+		t.Fatal(err)
+	}
+
+	if !CompareCards(m.mostRecentlyPlayed[1], Card{"K", "H"}) {
+		t.Errorf("Computer should head the trick with the king of hearts, played %v", m.mostRecentlyPlayed[1])
+	}
+}
+
+func TestComputerMeld(t *testing.T) {
+	c := Computer{}
+	table := Table{Trump: Card{"9", "D"}}
+	table.Hand = []Card{Card{"K", "D"}, Card{"Q", "D"}, Card{"K", "S"}, Card{"Q", "S"},
+		Card{"J", "D"}, Card{"K", "H"}, Card{"K", "C"}, Card{"9", "C"}}
+
+	expected := [][]Card{
+		{Card{"K", "D"}, Card{"K", "S"}, Card{"K", "H"}, Card{"K", "C"}},
+		{Card{"K", "D"}, Card{"Q", "D"}},
+		{Card{"Q", "S"}, Card{"J", "D"}},
+	}
+
+	for _, want := range expected {
+		meld, err := c.Meld(table)
+		if err != nil || !compareCardSlices(meld, want) {
+			t.Fatalf("Computer should meld %v after %v, got %v", want, table.Melds, meld)
+		}
+
+		table.Melds = append(table.Melds, meld)
+	}
+
+	// The spade marriage would bring no new card to the table.
+	if meld, _ := c.Meld(table); meld != nil {
+		t.Errorf("no legal melds remain after %v, got %v", table.Melds, meld)
+	}
+
+	// A melded queen can't marry again in the same class, but a second pair can.
+	table.Hand = []Card{Card{"K", "S"}, Card{"Q", "S"}}
+	table.Melds = [][]Card{{Card{"K", "S"}, Card{"Q", "S"}}}
+	if meld, _ := c.Meld(table); meld != nil {
+		t.Errorf("the marriage was already melded, got %v", meld)
+	}
+
+	table.Hand = append(table.Hand, Card{"K", "S"})
+	if meld, _ := c.Meld(table); meld != nil {
+		t.Errorf("the queen of spades is already married, got %v", meld)
+	}
+
+	table.Hand = append(table.Hand, Card{"Q", "S"})
+	if meld, _ := c.Meld(table); meld == nil {
+		t.Error("the second king and queen of spades should be melded")
+	}
+}
+
+func TestDeterminize(t *testing.T) {
+	playerOne := Human{}
+	m := InitializeMatch(&playerOne, &Computer{}, ClassicRules)
+	m.dealerPlayerOne = true
+	m.NewGame(false)
+	m.playerOneLed = false
+	m.playerOne.hand[1], m.playerTwo.hand[6] = m.playerTwo.hand[6], m.playerOne.hand[1]
+	m.playerTwo.melds = [][]Card{{Card{"K", "H"}, Card{"Q", "H"}}}
+	m.PlayerTwoPlayed(Card{"10", "C"})
+
+	table := m.table(0)
+	if len(table.OpponentShown) != 2 || len(table.Seen) != 1 || table.OpponentHandSize != 11 {
+		t.Fatalf("table doesn't show the opponent's meld and lead: %+v", table)
+	}
+
+	game := determinize(table, rand.New(rand.NewSource(1)))
+	if !containsCards(game.hands[1], table.OpponentShown) {
+		t.Errorf("the opponent's melded cards should stay in its hand: %v", game.hands[1])
+	}
+
+	if len(game.hands[1]) != 11 || len(game.stock) != 23 || !CompareCards(game.led, table.Led) {
+		t.Errorf("determinized deal has the wrong shape: %v, %v", game.hands[1], game.stock)
+	}
+
+	all := append(append(append([]Card{game.trump, game.led}, game.hands[0]...), game.hands[1]...), game.stock...)
+	if !compareCardSlices(all, buildDeck(nil).stack) {
+		t.Errorf("determinized deal isn't a full deck: %v", all)
+	}
+}
+
+func TestMonteCarloComputer(t *testing.T) {
+	searcher := Computer{Iterations: 20, Seed: 1}
+	m := InitializeMatch(&searcher, &Computer{}, playingTo(300))
+	if _, err := m.Run(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := NewComputer(Easy); c.searching() {
+		t.Error("an Easy Computer shouldn't search")
+	}
+
+	if c := NewComputer(Hard); !c.searching() || c.Budget == 0 {
+		t.Error("a Hard Computer should search within a time budget")
+	}
+}
+
+func BenchmarkComputerStrength(b *testing.B) {
+	for _, strength := range []Strength{Easy, Medium} {
+		wins := 0
+		for i := 0; i < b.N; i++ {
+			c := NewComputer(strength)
+			c.Seed = int64(i + 1)
+			m := InitializeMatch(c, &Computer{}, ClassicRules)
+			result, err := m.Run(true)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if result.PlayerOneWon {
+				wins++
+			}
+		}
+
+		b.Logf("strength %v won %v of %v matches against Easy", strength, wins, b.N)
+	}
+}
+
+func TestSolvePlayoff(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	m.NewGame(false)
+	if _, err := m.SolvePlayoff(); err == nil {
+		t.Error("the playoff can't be solved while the stock has cards")
+	}
+
+	m.deck.stack = nil
+	m.deck.trump = Card{"9", "S"}
+	m.playerOneLed = true
+
+	// Cashing the ace first wins a trick; leading the nine first loses both.
+	m.playerOne.hand = []Card{Card{"9", "H"}, Card{"A", "H"}}
+	m.playerTwo.hand = []Card{Card{"10", "H"}, Card{"9", "S"}}
+	solution, err := m.SolvePlayoff()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solution.PlayerOnePoints != 21 || solution.PlayerTwoPoints != 10 {
+		t.Errorf("playerOne should take 21 points and playerTwo 10: %+v", solution)
+	}
+
+	want := []Card{Card{"A", "H"}, Card{"10", "H"}, Card{"9", "H"}, Card{"9", "S"}}
+	for i := range want {
+		if i >= len(solution.Line) || !CompareCards(solution.Line[i], want[i]) {
+			t.Fatalf("optimal line should be %v, got %v", want, solution.Line)
+		}
+	}
+
+	// From the middle of a trick the solver must respect the card already led.
+	m.PlayerOnePlayed(Card{"9", "H"})
+	solution, _ = m.SolvePlayoff()
+	if solution.PlayerOnePoints != 0 || solution.PlayerTwoPoints != 31 {
+		t.Errorf("playerTwo should take every point after the nine is led: %+v", solution)
+	}
+}
+
+func TestSolvePlayoffMatchesPlay(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		m := InitializeMatch(&Computer{}, &Computer{}, ClassicRules)
+		m.NewGame(true)
+		for trickPhase, lastCard := m.TrickPhase(); trickPhase; trickPhase, lastCard = m.TrickPhase() {
+			m.playTrick()
+			m.drawAfterTrick(lastCard)
+		}
+
+		solution, err := m.SolvePlayoff()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(solution.Line) != 24 {
+			t.Fatalf("the line should cover all 12 tricks: %v", solution.Line)
+		}
+
+		// Following the line must reach exactly the solved score.
+		before := m.playerOne.currentTrickScore
+		for _, card := range solution.Line {
+			playerOne := m.playerOneLed
+			if m.inTrick[0] != m.inTrick[1] {
+				playerOne = m.inTrick[1]
+			}
+
+			var err error
+			if playerOne {
+				err = m.PlayerOnePlayed(card)
+			} else {
+				err = m.PlayerTwoPlayed(card)
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.inTrick[0] && m.inTrick[1] {
+				m.DecideTrickWinner()
+				m.AssignTrickPoints()
+			}
+		}
+
+		if got := m.ScoreHistory()[0].PlayerOneTricks - before; got != solution.PlayerOnePoints {
+			t.Errorf("playing the line gave playerOne %v, solved %v", got, solution.PlayerOnePoints)
+		}
+	}
+
+	// A searching Computer plays the solved line's first card.
+	m := InitializeMatch(&Human{}, &Computer{Iterations: 1}, ClassicRules)
+	m.NewGame(false)
+	m.deck.stack = nil
+	m.deck.trump = Card{"9", "S"}
+	m.playerOneLed = false
+	m.playerOne.hand = []Card{Card{"10", "H"}, Card{"9", "S"}}
+	m.playerTwo.hand = []Card{Card{"9", "H"}, Card{"A", "H"}}
+
+	// Every other card has been played.
+	m.played = buildDeck(nil).stack
+	for _, card := range append(m.PlayerOneHand(), m.PlayerTwoHand()...) {
+		_, idx := (&seat{hand: m.played}).handContains(card)
+		m.played = removeCard(m.played, idx)
+	}
+
+	if err := m.PlayerTwoPlayed(DummyCard); err != nil {
+		t.Fatal(err)
+	}
+
+	if !CompareCards(m.mostRecentlyPlayed[1], Card{"A", "H"}) {
+		t.Errorf("Computer should cash its ace first, played %v", m.mostRecentlyPlayed[1])
+	}
+}
+
+func TestEvaluateMeld(t *testing.T) {
+	trump := Card{"9", "H"}
+	hand := []Card{Card{"A", "H"}, Card{"10", "H"}, Card{"K", "H"}, Card{"Q", "H"}, Card{"J", "H"},
+		Card{"K", "S"}, Card{"Q", "S"}, Card{"K", "D"}, Card{"K", "C"}, Card{"J", "D"}, Card{"9", "H"}, Card{"9", "C"}}
+
+	score := EvaluateMeld(hand, trump)
+	if score.Total != 150+20+10+80+40 {
+		t.Errorf("hand should meld a run, a marriage, the dix, kings around and a pinochle: %+v", score)
+	}
+
+	names := make(map[string]int)
+	for _, item := range score.Items {
+		names[item.Name]++
+	}
+
+	for _, name := range []string{"run", "marriage", "dix", "kings around", "pinochle"} {
+		if names[name] != 1 {
+			t.Errorf("%v should be melded once: %+v", name, score)
+		}
+	}
+
+	aces := doubled([]Card{Card{"A", "S"}, Card{"A", "H"}, Card{"A", "C"}, Card{"A", "D"}})
+	if score := EvaluateMeld(aces, trump); score.Total != 1000 || len(score.Items) != 1 {
+		t.Errorf("eight aces are double aces for 1000: %+v", score)
+	}
+
+	pinochles := []Card{Card{"Q", "S"}, Card{"J", "D"}, Card{"Q", "S"}, Card{"J", "D"}}
+	if score := EvaluateMeld(pinochles, trump); score.Total != 300 {
+		t.Errorf("double pinochle is worth 300: %+v", score)
+	}
+
+	jacks := []Card{Card{"J", "S"}, Card{"J", "H"}, Card{"J", "C"}, Card{"J", "D"}}
+	if score := EvaluateMeld(jacks, trump); score.Total != 40 {
+		t.Errorf("jacks around is worth 40: %+v", score)
+	}
+
+	run := doubled(hand[:5])
+	if score := EvaluateMeld(run, trump); score.Total != 1500 {
+		t.Errorf("a double run is worth 1500: %+v", score)
+	}
+}
+
+func TestMeldReuse(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	m.NewGame(false)
+	m.deck.trump = Card{"9", "H"}
+	m.buildMeldSlices()
+	m.playerOne.hand = []Card{Card{"K", "S"}, Card{"Q", "S"}, Card{"K", "H"}, Card{"K", "D"},
+		Card{"K", "C"}, Card{"J", "D"}, Card{"9", "C"}, Card{"9", "C"}, Card{"9", "C"}}
+	m.playerTwo.hand = []Card{Card{"9", "D"}, Card{"9", "D"}, Card{"9", "D"}}
+
+	winTrick := func() {
+		m.playerOneLed = true
+		m.PlayerOnePlayed(Card{"9", "C"})
+		m.PlayerTwoPlayed(Card{"9", "D"})
+		m.DecideTrickWinner()
+	}
+
+	winTrick()
+	if !m.PlayerOneMeld([]Card{Card{"K", "S"}, Card{"Q", "S"}}) {
+		t.Fatal("the spade marriage should be melded")
+	}
+
+	winTrick()
+	if m.PlayerOneMeld([]Card{Card{"K", "S"}, Card{"Q", "S"}}) {
+		t.Error("the same marriage was melded twice")
+	}
+
+	if !m.PlayerOneMeld([]Card{Card{"K", "S"}, Card{"K", "H"}, Card{"K", "D"}, Card{"K", "C"}}) {
+		t.Error("the married king should serve again in kings around")
+	}
+
+	winTrick()
+	if !m.PlayerOneMeld([]Card{Card{"Q", "S"}, Card{"J", "D"}}) {
+		t.Error("the married queen should serve again in a pinochle")
+	}
+
+	items := m.PlayerOneMeldItems()
+	if len(items) != 3 || items[1].Name != "kings around" || m.playerOne.meldScore() != 20+80+40 {
+		t.Errorf("melds are itemized wrong: %+v", items)
+	}
+}
+
+func TestRoyalMarriageToRun(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	m.NewGame(false)
+	m.deck.trump = Card{"9", "H"}
+	m.buildMeldSlices()
+	m.playerOne.hand = []Card{Card{"A", "H"}, Card{"10", "H"}, Card{"K", "H"}, Card{"Q", "H"},
+		Card{"J", "H"}, Card{"9", "C"}, Card{"9", "C"}, Card{"9", "C"}}
+	m.playerTwo.hand = []Card{Card{"9", "D"}, Card{"9", "D"}, Card{"9", "D"}}
+
+	winTrick := func() {
+		m.playerOneLed = true
+		m.PlayerOnePlayed(Card{"9", "C"})
+		m.PlayerTwoPlayed(Card{"9", "D"})
+		m.DecideTrickWinner()
+	}
+
+	run := []Card{Card{"A", "H"}, Card{"10", "H"}, Card{"K", "H"}, Card{"Q", "H"}, Card{"J", "H"}}
+	winTrick()
+	if !m.PlayerOneMeld([]Card{Card{"K", "H"}, Card{"Q", "H"}}) {
+		t.Fatal("the royal marriage should be melded")
+	}
+
+	winTrick()
+	if !m.PlayerOneMeld(run) {
+		t.Fatalf("the royal marriage should be built up into a run: %v", m.PlayerOneMeldableCards())
+	}
+
+	if m.playerOne.meldScore() != 40+150 {
+		t.Errorf("the marriage and the run should score 190, got %v", m.playerOne.meldScore())
+	}
+
+	winTrick()
+	if m.PlayerOneMeld(run) || m.PlayerOneMeld([]Card{Card{"K", "H"}, Card{"Q", "H"}}) {
+		t.Error("the run or its marriage was melded again")
+	}
+}
+
+func TestExchangeDix(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	m.NewGame(false)
+	m.deck.trump = Card{"A", "H"}
+	m.buildMeldSlices()
+	m.playerOne.hand = []Card{Card{"9", "H"}, Card{"9", "C"}, Card{"9", "C"}}
+	m.playerTwo.hand = []Card{Card{"9", "D"}, Card{"9", "D"}}
+
+	if err := m.PlayerOneExchangeDix(); err == nil {
+		t.Error("the dix was exchanged before winning a trick")
+	}
+
+	m.playerOneLed = true
+	m.PlayerOnePlayed(Card{"9", "C"})
+	m.PlayerTwoPlayed(Card{"9", "D"})
+	m.DecideTrickWinner()
+	if err := m.PlayerTwoExchangeDix(); err == nil {
+		t.Error("playerTwo exchanged after losing the trick")
+	}
+
+	if err := m.PlayerOneExchangeDix(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.MeldWasSuccessful() {
+		t.Error("the exchange should count as a successful meld")
+	}
+
+	if ok, _ := m.playerOne.handContains(Card{"A", "H"}); !ok || !CompareCards(m.deck.trump, Card{"9", "H"}) {
+		t.Errorf("the nine should lie under the stock and the ace be in hand: %v, %v", m.deck.trump, m.PlayerOneHand())
+	}
+
+	if m.playerOne.meldScore() != 10 || m.PlayerOneMeld([]Card{Card{"9", "H"}}) {
+		t.Errorf("the exchange should score the dix as the trick's meld: %v", m.playerOne.meldScore())
+	}
+
+	m.PlayerOnePlayed(Card{"9", "C"})
+	m.PlayerTwoPlayed(Card{"9", "D"})
+	m.DecideTrickWinner()
+	if err := m.PlayerOneExchangeDix(); err == nil || m.MeldWasSuccessful() {
+		t.Error("the dix was exchanged twice")
+	}
+}
+
+func TestMatchScoring(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, playingTo(100))
+	finishGame := func(pOneScore, pTwoScore, pOneMeld, pTwoMeld int) {
+		m.NewGame(false)
+		m.deck.stack = nil
+		m.playerOne.currentScore, m.playerTwo.currentScore = pOneScore, pTwoScore
+		m.playerOne.currentMeldScore, m.playerTwo.currentMeldScore = pOneMeld, pTwoMeld
+		m.playerOne.hand = []Card{Card{"A", "S"}}
+		m.playerTwo.hand = []Card{Card{"10", "S"}}
+		m.playerOneLed = true
+		m.PlayerOnePlayed(Card{"A", "S"})
+		m.PlayerTwoPlayed(Card{"10", "S"})
+		m.DecideTrickWinner()
+		m.AssignTrickPoints()
+	}
+
+	// Both reach 100 in the same game without declaring out.
+	finishGame(60, 95, 20, 10)
+	if m.MatchOver() || m.PlayingTo() != 350 {
+		t.Errorf("both players crossed, so play should go on to 350: %v", m.PlayingTo())
+	}
+
+	if !m.PlayerOneWonGame() || m.PlayerOneScore() != 60+20+31 || m.PlayerTwoScore() != 105 {
+		t.Errorf("playerOne should have won the game: %v to %v", m.PlayerOneScore(), m.PlayerTwoScore())
+	}
+
+	finishGame(300, 200, 20, 0)
+	if !m.MatchOver() || !m.PlayerOneWonMatch() {
+		t.Errorf("playerOne reached 350 alone and should have won: %v", m.PlayerOneScore())
+	}
+
+	history := m.ScoreHistory()
+	want := HandScore{PlayerOneMeld: 20, PlayerOneTricks: 31, PlayerTwoMeld: 0, PlayerTwoTricks: 0, PlayingTo: 350}
+	if len(history) != 2 || history[1] != want {
+		t.Errorf("score history is wrong: %+v", history)
+	}
+}
+
+func TestDeclareOut(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, playingTo(100))
+	m.NewGame(false)
+	m.playerOne.currentScore = 70
+	m.playerOne.currentMeldScore = 20
+	m.playerOne.currentTrickScore = 10
+	if ok, err := m.PlayerOneDeclareOut(); err != nil || !ok {
+		t.Errorf("playerOne has 100 and should declare out: %v %v", ok, err)
+	}
+
+	if outcome := m.Outcome(); !outcome.Over || !outcome.PlayerOneWon || !outcome.DeclaredOut || outcome.WrongDeclaration {
+		t.Errorf("playerOne should have won by declaring out: %+v", outcome)
+	}
+
+	if _, err := m.PlayerTwoDeclareOut(); err == nil {
+		t.Error("declaring out of a finished match should fail")
+	}
+
+	m = InitializeMatch(&Human{}, &Human{}, playingTo(100))
+	m.NewGame(false)
+	m.playerTwo.currentScore = 70
+	m.playerTwo.currentMeldScore = 20
+	if ok, err := m.PlayerTwoDeclareOut(); err != nil || ok {
+		t.Errorf("playerTwo has only 90 and should be wrong: %v %v", ok, err)
+	}
+
+	if !m.MatchOver() || !m.PlayerOneWonMatch() || !m.Outcome().WrongDeclaration {
+		t.Errorf("a wrong declaration should lose the match: %+v", m.Outcome())
+	}
+
+	if history := m.ScoreHistory(); len(history) != 1 || history[0].PlayerTwoMeld != 20 {
+		t.Errorf("the interrupted game should be in the history: %+v", history)
+	}
+}
+
+// playingTo returns ClassicRules played to target.
+func playingTo(target int) Rules {
+	rules := ClassicRules
+	rules.PlayingTo = target
+	return rules
+}
+
+func TestRules(t *testing.T) {
+	m := InitializeMatch(&Human{}, &Human{}, CountersRules)
+	m.NewGame(false)
+	m.deck.stack = nil
+	m.playerOne.hand = []Card{Card{"K", "S"}}
+	m.playerTwo.hand = []Card{Card{"Q", "S"}}
+	m.playerOneLed = true
+	m.PlayerOnePlayed(Card{"K", "S"})
+	m.PlayerTwoPlayed(Card{"Q", "S"})
+	m.DecideTrickWinner()
+	m.AssignTrickPoints()
+	if m.PlayerOneScore() != 10+0+10 {
+		t.Errorf("a king and a queen with the last trick count 20 under CountersRules: %v", m.PlayerOneScore())
+	}
+
+	rules := ClassicRules
+	rules.Dix = DixNoExchange
+	m = InitializeMatch(&Human{}, &Human{}, rules)
+	m.NewGame(false)
+	m.deck.trump = Card{"A", "H"}
+	m.buildMeldSlices()
+	m.playerOne.hand = []Card{Card{"9", "H"}, Card{"9", "C"}}
+	m.playerTwo.hand = []Card{Card{"9", "D"}}
+	m.playerOneLed = true
+	m.PlayerOnePlayed(Card{"9", "C"})
+	m.PlayerTwoPlayed(Card{"9", "D"})
+	m.DecideTrickWinner()
+	if err := m.PlayerOneExchangeDix(); err == nil {
+		t.Error("the dix was exchanged under DixNoExchange")
+	}
+
+	if !m.PlayerOneMeld([]Card{Card{"9", "H"}}) {
+		t.Error("the dix should still be meldable under DixNoExchange")
+	}
+
+	m = InitializeMatch(&Human{}, &Human{}, FifteenHundredRules)
+	seed := int64(0)
+	for ; m.deck.trump.faceValue != "9"; seed++ {
+		m.NewGameWithSeed(seed)
+	}
+
+	for _, dealerPlayerOne := range []bool{true, false} {
+		m = InitializeMatch(&Human{}, &Human{}, FifteenHundredRules)
+		m.dealerPlayerOne = dealerPlayerOne
+		m.NewGameWithSeed(seed - 1)
+		dealer, other := m.playerTwo, m.playerOne
+		if dealerPlayerOne {
+			dealer, other = m.playerOne, m.playerTwo
+		}
+
+		if m.deck.trump.faceValue != "9" || dealer.meldScore() != 10 || other.meldScore() != 0 {
+			t.Errorf("the dealer should score the turned-up %v when playerOne dealing is %v: %v, %v",
+				m.deck.trump, dealerPlayerOne, dealer.meldScore(), other.meldScore())
+		}
+
+		if m.playerOneLed == dealerPlayerOne {
+			t.Errorf("the dealer should not lead the first trick when playerOne dealing is %v", dealerPlayerOne)
+		}
+	}
+
+	rules = ClassicRules
+	rules.PlayingTo, rules.TargetRaise = 100, 0
+	m = InitializeMatch(&Human{}, &Human{}, rules)
+	m.NewGame(false)
+	m.deck.stack = nil
+	m.playerOne.currentScore, m.playerTwo.currentScore = 120, 110
+	m.playerOne.hand = []Card{Card{"9", "S"}}
+	m.playerTwo.hand = []Card{Card{"A", "S"}}
+	m.playerOneLed = true
+	m.PlayerOnePlayed(Card{"9", "S"})
+	m.PlayerTwoPlayed(Card{"A", "S"})
+	m.DecideTrickWinner()
+	m.AssignTrickPoints()
+	if !m.MatchOver() || m.PlayerOneWonMatch() || m.PlayingTo() != 100 {
+		t.Errorf("without a target raise the higher score should win: %v to %v", m.PlayerOneScore(), m.PlayerTwoScore())
+	}
+}
+
+func TestTrickRules(t *testing.T) {
+	trick := []Card{Card{"10", "S"}, Card{"A", "S"}, Card{"A", "S"}}
+	if winner := trickWinner(trick, "H"); winner != 1 {
+		t.Errorf("the first of two identical aces should win: %v", winner)
+	}
+
+	trick = []Card{Card{"A", "S"}, Card{"9", "H"}}
+	if winner := trickWinner(trick, "H"); winner != 1 {
+		t.Errorf("the trump should win: %v", winner)
+	}
+
+	hand := []Card{Card{"K", "S"}, Card{"10", "H"}, Card{"J", "H"}}
+	if err := trickViolation(hand, trick, Card{"K", "S"}, "H"); err != nil {
+		t.Errorf("following suit is always allowed once the trick is trumped: %v", err)
+	}
+
+	hand = []Card{Card{"10", "H"}, Card{"J", "H"}, Card{"9", "C"}}
+	if err := trickViolation(hand, trick, Card{"J", "H"}, "H"); err != nil {
+		t.Errorf("the jack of trump beats the nine: %v", err)
+	}
+
+	if err := trickViolation(hand, trick, Card{"9", "C"}, "H"); err == nil {
+		t.Error("a player void in the led suit must trump")
+	}
+
+	trick = []Card{Card{"A", "S"}, Card{"K", "H"}}
+	if legal := trickLegal(hand, trick, "H"); !compareCardSlices(legal, []Card{Card{"10", "H"}}) {
+		t.Errorf("only the ten of trump beats the king: %v", legal)
+	}
+}
+
+func TestAuction(t *testing.T) {
+	rules := BidRules{Minimum: 300, Increment: 10, StuckDealer: true}
+	a := NewAuction(3, 2, rules)
+	if err := a.Bid(1, 300); err == nil {
+		t.Error("player 1 bid out of turn")
+	}
+
+	if err := a.Bid(0, 290); err == nil {
+		t.Error("a bid below the minimum was accepted")
+	}
+
+	a.Bid(0, 300)
+	a.Bid(1, 320)
+	a.Pass(2)
+	if err := a.Bid(0, 325); err == nil {
+		t.Error("a bid off the increment was accepted")
+	}
+
+	if _, ok := a.Contract(); ok || a.Minimum() != 330 {
+		t.Errorf("the auction is still open, with 330 the least raise: %v", a.Minimum())
+	}
+
+	a.Pass(0)
+	if contract, ok := a.Contract(); !ok || contract.Bidder != 1 || contract.Bid != 320 {
+		t.Errorf("player 1 should take the contract at 320: %+v", contract)
+	}
+
+	a = NewAuction(3, 2, rules)
+	a.Pass(0)
+	a.Pass(1)
+	a.Pass(2)
+	if contract, ok := a.Contract(); !ok || contract.Bidder != 2 || contract.Bid != 300 {
+		t.Errorf("the dealer should be stuck with the minimum: %+v", contract)
+	}
+
+	rules.StuckDealer = false
+	a = NewAuction(3, 2, rules)
+	a.Pass(0)
+	a.Pass(1)
+	if err := a.Pass(2); err != nil || !a.Done() {
+		t.Fatalf("the dealer should be able to pass too: %v", err)
+	}
+
+	if _, ok := a.Contract(); ok {
+		t.Error("with no stuck dealer the hand should be thrown in")
+	}
+
+	contract := Contract{Bidder: 0, Bid: 350}
+	if !contract.Made(200, 150) || contract.Score(200, 150) != 350 || contract.Score(200, 149) != -350 {
+		t.Error("a contract is made by meld and counters together, and set by the bid")
+	}
+
+	m := InitializeCutthroat(&Human{}, &Human{}, &Human{}, ClassicRules)
+	m.rules.Bidding.StuckDealer = false
+	m.NewGame(false)
+	m.Pass(1)
+	m.Pass(2)
+	m.Pass(0)
+	if m.Bidding() || m.Contract().Bidder != -1 || !m.ScoreHistory()[0].ThrownIn {
+		t.Errorf("the hand should have been thrown in: %+v", m.ScoreHistory())
+	}
+}
+
+func TestCutthroat(t *testing.T) {
+	m := InitializeCutthroat(&Human{}, &Human{}, &Human{}, ClassicRules)
+	m.NewGame(true)
+	if m.Dealer() != 0 || m.Turn() != 1 || m.Widow() != nil {
+		t.Fatalf("player 0 should deal and player 1 bid first: %v %v", m.Dealer(), m.Turn())
+	}
+
+	for i := 0; i < 3; i++ {
+		if len(m.Hand(i)) != 15 {
+			t.Errorf("player %v holds %v cards", i, len(m.Hand(i)))
+		}
+	}
+
+	m.Bid(1, 300)
+	m.Pass(2)
+	m.Pass(0)
+	contract := m.Contract()
+	if contract.Bidder != 1 || contract.Bid != 300 || len(m.Hand(1)) != 18 || len(m.Widow()) != 3 {
+		t.Fatalf("player 1 should have the contract and the widow: %+v %v", contract, m.Hand(1))
+	}
+
+	if err := m.Bury(m.Hand(1)[:3]); err == nil {
+		t.Error("cards were buried before trump was named")
+	}
+
+	if err := m.NameTrump("H"); err != nil {
+		t.Fatal(err)
+	}
+
+	buried := append([]Card(nil), m.Hand(1)[:3]...)
+	if err := m.Bury(buried); err != nil {
+		t.Fatal(err)
+	}
+
+	buriedPoints := 0
+	for _, card := range buried {
+		buriedPoints += m.pointValues.cardPoints(card)
+	}
+
+	if m.Melds(2).Total != bestMelds(m.Hand(2), nil, "H", m.pointValues).Total {
+		t.Errorf("player 2 should meld the best of their hand: %+v", m.Melds(2))
+	}
+
+	if m.Turn() != 1 || !m.Playing() {
+		t.Fatal("the bidder should lead the first trick")
+	}
+
+	if err := m.Play(2, m.Hand(2)[0]); err == nil {
+		t.Error("player 2 played out of turn")
+	}
+
+	for m.Playing() {
+		player := m.Turn()
+		if err := m.Play(player, m.LegalPlays(player)[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history := m.ScoreHistory()
+	counters := 0
+	for _, c := range history[0].Counters {
+		counters += c
+	}
+
+	if len(history) != 1 || counters != 250 {
+		t.Errorf("every counter, the buried %v included, should be scored: %+v", buriedPoints, history)
+	}
+
+	hand := history[0]
+	want := -300
+	if hand.Made {
+		want = hand.Melds[1] + hand.Counters[1]
+	}
+
+	if m.Scores()[1] != want {
+		t.Errorf("the bidder should score %v: %v", want, m.Scores())
+	}
+}
+
+func TestCutthroatRun(t *testing.T) {
+	m := InitializeCutthroat(&Computer{}, &Computer{}, &Computer{}, playingTo(500))
+	m.SetSeed(3)
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.MatchOver() || result.Scores[result.Winner] < 500 {
+		t.Errorf("the winner should have reached 500: %+v", result)
+	}
+}
+
+func TestAuctionMeldNeedsTrick(t *testing.T) {
+	m := InitializeCutthroat(&Human{}, &Human{}, &Human{}, ClassicRules)
+	m.NewGame(true)
+	m.contract = Contract{Bidder: 1, Bid: 300}
+	for i, s := range m.seats {
+		s.currentMeldScore, s.currentTrickScore = 400, 0
+		m.taken[i] = 0
+	}
+
+	m.taken[2] = 8
+	m.seats[2].currentTrickScore = 250
+	m.scoreHand()
+	hand := m.ScoreHistory()[0]
+	if hand.Melds[1] != 0 || hand.Melds[0] != 0 || hand.Melds[2] != 400 {
+		t.Errorf("only the side that took a trick should keep its meld: %v", hand.Melds)
+	}
+
+	if hand.Made || m.Scores()[1] != -300 {
+		t.Errorf("the bidder took no trick, and should be set: %+v %v", hand, m.Scores())
+	}
+}
+
+func TestPartnership(t *testing.T) {
+	m := InitializePartnership(&Human{}, &Human{}, &Human{}, &Human{}, ClassicRules)
+	m.SetSeed(5)
+	m.NewGame(true)
+	for i := 0; i < 4; i++ {
+		if len(m.Hand(i)) != 12 {
+			t.Errorf("player %v holds %v cards", i, len(m.Hand(i)))
+		}
+	}
+
+	if m.Partner(1) != 3 || m.Side(2) != 0 || m.Widow() != nil {
+		t.Error("players 0 and 2 should partner against 1 and 3")
+	}
+
+	m.Pass(1)
+	m.Bid(2, 300)
+	m.Pass(3)
+	m.Pass(0)
+	m.NameTrump("S")
+	if m.Turn() != 0 {
+		t.Fatalf("the bidder's partner should pass first: %v", m.Turn())
+	}
+
+	if err := m.PassCards(2, m.Hand(2)[:4]); err == nil {
+		t.Error("the bidder passed before their partner")
+	}
+
+	toBidder := append([]Card(nil), m.Hand(0)[:4]...)
+	m.PassCards(0, toBidder)
+	if len(m.Hand(2)) != 16 || !containsCards(m.Hand(2), toBidder) {
+		t.Fatalf("the bidder should hold the passed cards: %v", m.Hand(2))
+	}
+
+	m.PassCards(2, m.Hand(2)[:4])
+	if !m.Playing() || m.Turn() != 2 || len(m.Hand(0)) != 12 {
+		t.Fatal("play should start with the bidder's lead once both have passed")
+	}
+
+	for m.Playing() {
+		player := m.Turn()
+		if err := m.Play(player, m.LegalPlays(player)[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hand := m.ScoreHistory()[0]
+	if hand.Counters[0]+hand.Counters[1] != 250 {
+		t.Errorf("the counters should be scored by side: %+v", hand)
+	}
+
+	want := []int{-300, hand.Melds[1] + hand.Counters[1]}
+	if hand.Made {
+		want[0] = hand.Melds[0] + hand.Counters[0]
+	}
+
+	if scores := m.Scores(); scores[0] != want[0] || scores[1] != want[1] {
+		t.Errorf("the sides should score %v: %v", want, scores)
+	}
+
+	m = InitializePartnership(&Computer{}, &Computer{}, &Computer{}, &Computer{}, playingTo(500))
+	m.SetSeed(5)
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Scores) != 2 || result.Scores[result.Winner] < 500 {
+		t.Errorf("a side should have reached 500: %+v", result)
+	}
+}
+
+func TestDoubleDeck(t *testing.T) {
+	d := doubleDeck.build(NewSeededShuffler(1))
+	counts := make(map[Card]int)
+	for _, card := range d.stack {
+		counts[card]++
+	}
+
+	if len(d.stack) != 80 || counts[Card{"A", "S"}] != 4 || counts[Card{"9", "S"}] != 0 {
+		t.Errorf("the double deck should hold four of every card but the nines: %v", counts)
+	}
+
+	m := InitializeDoubleDeck(&Human{}, &Human{}, &Human{}, &Human{}, DoubleDeckRules)
+	m.NewGame(true)
+	for i := 0; i < 4; i++ {
+		if len(m.Hand(i)) != 20 {
+			t.Errorf("player %v holds %v cards", i, len(m.Hand(i)))
+		}
+	}
+
+	values := DoubleDeckRules.points()
+	aces := repeated([]Card{Card{"A", "S"}, Card{"A", "H"}, Card{"A", "C"}, Card{"A", "D"}}, 3)
+	hand := append(aces, Card{"A", "S"}, Card{"Q", "S"}, Card{"Q", "S"}, Card{"Q", "S"}, Card{"J", "D"}, Card{"J", "D"}, Card{"J", "D"})
+	score := bestMelds(hand, nil, "H", values)
+	if score.Total != 1500+600 || len(score.Items) != 2 {
+		t.Errorf("expected triple aces and a triple pinochle: %+v", score)
+	}
+
+	if score := bestMelds(hand, nil, "H", ClassicRules.points()); score.Total != 1000+100+300+40 {
+		t.Errorf("single-deck rules don't value triples: %+v", score)
+	}
+
+	m = InitializeDoubleDeck(&Computer{}, &Computer{}, &Computer{}, &Computer{}, DoubleDeckRules)
+	m.rules.PlayingTo = 1500
+	m.SetSeed(2)
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Scores[result.Winner] < 1500 {
+		t.Errorf("a side should have reached 1500: %+v", result)
+	}
+}
+
+func TestTrick(t *testing.T) {
+	trick := NewTrick(2, 4, "H", ClassicRules)
+	if trick.LedSuit() != "" || trick.Next() != 2 {
+		t.Errorf("seat 2 should lead: %v", trick.Next())
+	}
+
+	for _, card := range []Card{Card{"10", "S"}, Card{"A", "S"}, Card{"K", "H"}, Card{"A", "S"}} {
+		if err := trick.Play(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := trick.Play(Card{"9", "S"}); err == nil {
+		t.Error("a fifth card was played to a four-handed trick")
+	}
+
+	if winner, points := trick.Resolve(); winner != 0 || points != 10+11+4+11 {
+		t.Errorf("seat 0 should take 36 with the king of trump: %v %v", winner, points)
+	}
+
+	trick = NewTrick(1, 3, "H", CountersRules)
+	trick.Play(Card{"A", "D"})
+	trick.Play(Card{"A", "D"})
+	if legal := trick.Legal([]Card{Card{"9", "D"}, Card{"9", "H"}}); !compareCardSlices(legal, []Card{Card{"9", "D"}}) {
+		t.Errorf("the diamond must be followed: %v", legal)
+	}
+
+	trick.Play(Card{"9", "D"})
+	if winner, points := trick.Resolve(); winner != 1 || points != 20 || trick.LedSuit() != "D" || trick.Seat(2) != 0 {
+		t.Errorf("the leader's ace was played first and should win: %v %v", winner, points)
+	}
+
+	m := InitializeMatch(&Human{}, &Human{}, ClassicRules)
+	m.NewGame(false)
+	m.deck.trump = Card{"A", "C"}
+	m.playerOne.hand = []Card{Card{"K", "S"}}
+	m.playerTwo.hand = []Card{Card{"K", "S"}}
+	m.playerOneLed = false
+	m.PlayerTwoPlayed(Card{"K", "S"})
+	m.PlayerOnePlayed(Card{"K", "S"})
+	m.DecideTrickWinner()
+	if last := m.LastTrick(); m.PlayerOneWonTrick() || last.Leader() != 1 || !compareCardSlices(last.Plays(), []Card{Card{"K", "S"}, Card{"K", "S"}}) {
+		t.Errorf("playerTwo led the first king and should take the trick: %+v", last)
+	}
+}
+
+func TestParseCard(t *testing.T) {
+	for _, suit := range suits {
+		for _, face := range faceValues {
+			card := Card{face, suit}
+			parsed, err := ParseCard(card.String())
+			if err != nil || parsed != card {
+				t.Errorf("%v should round-trip: %v %v", card, parsed, err)
+			}
+
+			if parsed, err := ParseCard(card.Symbol()); err != nil || parsed != card {
+				t.Errorf("%v should round-trip: %v %v", card.Symbol(), parsed, err)
+			}
+		}
+	}
+
+	if card, err := ParseCard(" th "); err != nil || card.Rank() != Ten || card.Suit() != Hearts {
+		t.Errorf("th should be the ten of hearts: %v %v", card, err)
+	}
+
+	for _, text := range []string{"", "H", "8H", "AX", "1H", "10"} {
+		if _, err := ParseCard(text); err == nil {
+			t.Errorf("%q should not parse", text)
+		}
+	}
+
+	if _, err := ParseCard("a♦♦"); err == nil || err.Error() != `"a♦♦" is not a card: "A♦" is not a pinochle rank` {
+		t.Errorf("the error should quote the card as typed: %v", err)
+	}
+
+	if _, err := NewCard(Nine, "X"); err == nil {
+		t.Error("a card of suit X was made")
+	}
+
+	cards, err := ParseCards("KH, QH 9♣")
+	want := []Card{Card{"K", "H"}, Card{"Q", "H"}, Card{"9", "C"}}
+	if err != nil || len(cards) != 3 || !compareCardSlices(cards, want) {
+		t.Errorf("expected %v: %v %v", want, cards, err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	m := InitializeMatch(&Computer{}, &Computer{}, playingTo(500))
+	m.SetSeed(11)
+	var dealt, turned, played, tricks, scored, over int
+	melds := [2]int{}
+	var games []HandScore
+	m.Subscribe(ObserverFunc(func(event Event) {
+		switch e := event.(type) {
+		case CardDealt:
+			dealt++
+		case TrumpTurned:
+			turned++
+		case CardPlayed:
+			played++
+		case TrickWon:
+			tricks++
+			if !e.Trick.Complete() {
+				t.Errorf("trick %v was won before it was complete", e.Trick.Plays())
+			}
+		case MeldDeclared:
+			melds[e.Seat] += e.Meld.Points
+		case HandScored:
+			scored++
+			games = append(games, e.Score)
+			if e.Score.PlayerOneMeld != melds[0] || e.Score.PlayerTwoMeld != melds[1] {
+				t.Errorf("melds of %v were declared for a game scoring %+v", melds, e.Score)
+			}
+
+			melds = [2]int{}
+		case GameOver:
+			over++
+			if !e.Outcome.Over || e.PlayerOneScore != m.PlayerOneScore() {
+				t.Errorf("game over was sent with %+v", e)
+			}
+		}
+	}))
+
+	result, err := m.Run(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scored != result.Games || len(games) != len(m.ScoreHistory()) {
+		t.Fatalf("%v games were scored, but the history is %+v", scored, m.ScoreHistory())
+	}
+
+	for i, game := range m.ScoreHistory() {
+		if games[i] != game {
+			t.Errorf("game %v was scored as %+v, but recorded as %+v", i, games[i], game)
+		}
+	}
+
+	if dealt != 48*result.Games || turned != result.Games || played != 48*result.Games || tricks != 24*result.Games {
+		t.Errorf("%v games sent %v deals, %v trumps, %v plays and %v tricks", result.Games, dealt, turned, played, tricks)
+	}
+
+	if over != 1 {
+		t.Errorf("game over was sent %v times", over)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	rules := FifteenHundredRules
+	rules.PlayingTo = 600
+	m := InitializeMatch(&Computer{}, &Computer{}, rules)
+	m.SetSeed(21)
+	var events []Event
+	m.Subscribe(ObserverFunc(func(event Event) {
+		events = append(events, event)
+	}))
+
+	var text bytes.Buffer
+	writer := NewRecordWriter(&text, &m, map[string]string{"PlayerOne": "Ann", "Date": "2024.03.01"})
+	m.Subscribe(writer)
+	if _, err := m.Run(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if writer.Err() != nil {
+		t.Fatal(writer.Err())
+	}
+
+	record, err := ParseRecord(bytes.NewReader(text.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%v", err, text.String())
+	}
+
+	if record.Tags["PlayerOne"] != "Ann" || record.Rules != rules || len(record.Games) != len(m.ScoreHistory()) {
+		t.Fatalf("the record read back as %v, %+v and %v games", record.Tags, record.Rules, len(record.Games))
+	}
+
+	var parsed []Event
+	for _, game := range record.Games {
+		parsed = append(parsed, game.Events...)
+	}
+
+	describe := func(event Event) string {
+		if won, ok := event.(TrickWon); ok {
+			return fmt.Sprintf("trick %v %v led by %v: %v", won.Seat, won.Points, won.Trick.Leader(), won.Trick.Plays())
+		}
+
+		return fmt.Sprintf("%#v", event)
+	}
+
+	if len(parsed) != len(events) {
+		t.Fatalf("%v events were recorded, but %v read back", len(events), len(parsed))
+	}
+
+	for i := range events {
+		if describe(parsed[i]) != describe(events[i]) {
+			t.Fatalf("event %v was %v, but read back as %v", i, describe(events[i]), describe(parsed[i]))
+		}
+	}
+
+	var again bytes.Buffer
+	if err := record.Write(&again); err != nil || again.String() != text.String() {
+		t.Errorf("the record was written again as\n%v", again.String())
+	}
+
+	stuck := ClassicRules
+	stuck.Bidding = BidRules{StuckDealer: true}
+	text.Reset()
+	if err := (&Record{Rules: stuck}).Write(&text); err != nil {
+		t.Fatal(err)
+	}
+
+	if record, err := ParseRecord(bytes.NewReader(text.Bytes())); err != nil || record.Rules != stuck {
+		t.Errorf("a stuck dealer without a minimum bid was written as\n%v", text.String())
+	}
+
+	for _, bad := range []string{"Play 1 AS", "Game 1 seed 1\nPlay 3 AS", "Game 1 seed 1\nMeld 1 marriage 20 KS QS", "[Dix \"Sometimes\"]"} {
+		if _, err := ParseRecord(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q was parsed", bad)
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package pinochle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is a match read from a game record. A game record is a match written
+// out as text, in the manner of chess's PGN. It opens with tags, one per line,
+// such as
+//
+//	[PlayerOne "Ann"]
+//	[Date "2024.03.01"]
+//
+// and the rules the match is played under, also as tags. Each game follows,
+// headed by the seed it was shuffled with, and then one line per event, with
+// playerOne as 1 and playerTwo as 2:
+//
+//	Game 1 seed 42
+//	Deal 2 9H KS AD       cards dealt to one hand, in order
+//	Trump QC              the card turned up for trump
+//	Play 1 10S            a card played to a trick
+//	Trick 1 21            the trick's winner, and the counters in it
+//	Meld 1 "marriage" 20 KD QD
+//	Exchange 2 "dix" 10 9C
+//	Score 20 130 40 120 1000
+//	Over 20 130 1-0 declared
+//
+// Score gives each player's meld and tricks for the game, and the score that
+// then wins the match. Over gives the final scores and the result, and whether
+// a player declared out, rightly or wrongly. Anything after a semicolon is a
+// comment.
+type Record struct {
+	Tags  map[string]string // every tag but the rules
+	Rules Rules
+	Games []GameRecord
+}
+
+// GameRecord is one game of a Record: the seed it was shuffled with, and its
+// events in order. The TrickWon events hold Tricks rebuilt from the plays.
+type GameRecord struct {
+	Seed   int64
+	Events []Event
+}
+
+// tagOrder is the order the usual tags are written in; any others follow in
+// alphabetical order.
+var tagOrder = []string{"Event", "Site", "Date", "PlayerOne", "PlayerTwo"}
+
+var dixRuleNames = map[DixRule]string{
+	DixExchange:     "Exchange",
+	DixNoExchange:   "NoExchange",
+	DixDealerScores: "DealerScores",
+}
+
+// ruleField is a value of the Rules, written in the tag group as name=value.
+type ruleField struct {
+	group, name string
+	value       *int
+}
+
+// fields lists the integer values of rules, by tag group.
+func (rules *Rules) fields() []ruleField {
+	c, m, b := &rules.Counters, &rules.Melds, &rules.Bidding
+	return []ruleField{
+		{"Counters", "Ace", &c.Ace}, {"Counters", "Ten", &c.Ten}, {"Counters", "King", &c.King},
+		{"Counters", "Queen", &c.Queen}, {"Counters", "Jack", &c.Jack},
+		{"Melds", "Flush", &m.Flush}, {"Melds", "DoubleRun", &m.DoubleRun},
+		{"Melds", "TripleRun", &m.TripleRun}, {"Melds", "QuadrupleRun", &m.QuadrupleRun},
+		{"Melds", "RoyalMarriage", &m.RoyalMarriage}, {"Melds", "Marriage", &m.Marriage}, {"Melds", "Dix", &m.Dix},
+		{"Melds", "Aces", &m.Aces}, {"Melds", "Kings", &m.Kings}, {"Melds", "Queens", &m.Queens}, {"Melds", "Jacks", &m.Jacks},
+		{"Melds", "DoubleAces", &m.DoubleAces}, {"Melds", "DoubleKings", &m.DoubleKings},
+		{"Melds", "DoubleQueens", &m.DoubleQueens}, {"Melds", "DoubleJacks", &m.DoubleJacks},
+		{"Melds", "TripleAces", &m.TripleAces}, {"Melds", "TripleKings", &m.TripleKings},
+		{"Melds", "TripleQueens", &m.TripleQueens}, {"Melds", "TripleJacks", &m.TripleJacks},
+		{"Melds", "QuadrupleAces", &m.QuadrupleAces}, {"Melds", "QuadrupleKings", &m.QuadrupleKings},
+		{"Melds", "QuadrupleQueens", &m.QuadrupleQueens}, {"Melds", "QuadrupleJacks", &m.QuadrupleJacks},
+		{"Melds", "Pinochle", &m.Pinochle}, {"Melds", "DoublePinochle", &m.DoublePinochle},
+		{"Melds", "TriplePinochle", &m.TriplePinochle}, {"Melds", "QuadruplePinochle", &m.QuadruplePinochle},
+		{"Scoring", "LastTrick", &rules.LastTrick}, {"Scoring", "PlayingTo", &rules.PlayingTo},
+		{"Scoring", "TargetRaise", &rules.TargetRaise},
+		{"Bidding", "Minimum", &b.Minimum}, {"Bidding", "Increment", &b.Increment},
+	}
+}
+
+// ruleTags returns the rules as tags, in the order they are written.
+func (rules Rules) ruleTags() [][2]string {
+	var groups []string
+	values := make(map[string][]string)
+	add := func(group, value string) {
+		if _, ok := values[group]; !ok {
+			groups = append(groups, group)
+		}
+
+		values[group] = append(values[group], value)
+	}
+
+	for _, field := range rules.fields() {
+		if *field.value != 0 || field.group == "Scoring" {
+			add(field.group, fmt.Sprintf("%v=%v", field.name, *field.value))
+		}
+	}
+
+	if rules.Bidding.StuckDealer {
+		add("Bidding", "StuckDealer")
+	}
+
+	var tags [][2]string
+	for _, group := range groups {
+		tags = append(tags, [2]string{group, strings.Join(values[group], " ")})
+	}
+
+	return append(tags, [2]string{"Dix", dixRuleNames[rules.Dix]})
+}
+
+// isRulesTag reports whether name is one of the tags written by ruleTags.
+func isRulesTag(name string) bool {
+	for _, field := range (&Rules{}).fields() {
+		if field.group == name {
+			return true
+		}
+	}
+
+	return name == "Dix"
+}
+
+// setTag sets the rules from a tag written by ruleTags.
+func (rules *Rules) setTag(name, value string) error {
+	if name == "Dix" {
+		for rule, ruleName := range dixRuleNames {
+			if ruleName == value {
+				rules.Dix = rule
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unknown dix rule %q", value)
+	}
+
+	fields := make(map[string]*int)
+	for _, field := range rules.fields() {
+		if field.group == name {
+			fields[field.name] = field.value
+		}
+	}
+
+	for _, item := range strings.Fields(value) {
+		if name == "Bidding" && item == "StuckDealer" {
+			rules.Bidding.StuckDealer = true
+			continue
+		}
+
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || fields[parts[0]] == nil {
+			return fmt.Errorf("%q is not a %v value", item, name)
+		}
+
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("%q is not a %v value: %v", item, name, err)
+		}
+
+		*fields[parts[0]] = n
+	}
+
+	return nil
+}
+
+// RecordWriter is an Observer that writes the record of a Match as it is
+// played. Subscribe it to the Match before the first game is dealt.
+type RecordWriter struct {
+	w     io.Writer
+	tags  map[string]string
+	rules Rules
+	seed  func() int64
+
+	started bool
+	inGame  bool
+	games   int
+	deal    []Card // dealt to seat and not yet written
+	seat    int
+	err     error
+}
+
+// NewRecordWriter returns a RecordWriter writing the record of match to w, with
+// tags such as PlayerOne, PlayerTwo or Event. A Date tag for today is added
+// unless tags has one.
+func NewRecordWriter(w io.Writer, match *Match, tags map[string]string) *RecordWriter {
+	all := map[string]string{"Date": time.Now().Format("2006.01.02")}
+	for name, value := range tags {
+		all[name] = value
+	}
+
+	return &RecordWriter{w: w, tags: all, rules: match.Rules(), seed: match.Seed}
+}
+
+// Err returns the first error met writing the record, if any.
+func (rw *RecordWriter) Err() error {
+	return rw.err
+}
+
+// Observe writes event to the record.
+func (rw *RecordWriter) Observe(event Event) {
+	if !rw.started {
+		rw.writeTags()
+		rw.started = true
+	}
+
+	if dealt, ok := event.(CardDealt); !ok || dealt.Seat != rw.seat {
+		rw.flushDeal()
+	}
+
+	switch e := event.(type) {
+	case CardDealt:
+		if !rw.inGame {
+			var seed int64
+			if rw.seed != nil {
+				seed = rw.seed()
+			}
+
+			rw.startGame(seed)
+		}
+
+		rw.seat = e.Seat
+		rw.deal = append(rw.deal, e.Card)
+	case TrumpTurned:
+		rw.printf("Trump %v\n", e.Card)
+	case CardPlayed:
+		rw.printf("Play %v %v\n", e.Seat+1, e.Card)
+	case TrickWon:
+		rw.printf("Trick %v %v\n", e.Seat+1, e.Points)
+	case MeldDeclared:
+		keyword := "Meld"
+		if e.Exchanged {
+			keyword = "Exchange"
+		}
+
+		rw.printf("%v %v %q %v %v\n", keyword, e.Seat+1, e.Meld.Name, e.Meld.Points, joinCards(e.Meld.Cards))
+	case HandScored:
+		s := e.Score
+		rw.printf("Score %v %v %v %v %v\n", s.PlayerOneMeld, s.PlayerOneTricks, s.PlayerTwoMeld, s.PlayerTwoTricks, s.PlayingTo)
+		rw.inGame = false
+	case GameOver:
+		result := "0-1"
+		if e.Outcome.PlayerOneWon {
+			result = "1-0"
+		}
+
+		switch {
+		case e.Outcome.WrongDeclaration:
+			result += " wrong"
+		case e.Outcome.DeclaredOut:
+			result += " declared"
+		}
+
+		rw.printf("Over %v %v %v\n", e.PlayerOneScore, e.PlayerTwoScore, result)
+	}
+}
+
+func (rw *RecordWriter) startGame(seed int64) {
+	rw.games++
+	rw.inGame = true
+	rw.printf("\nGame %v seed %v\n", rw.games, seed)
+}
+
+func (rw *RecordWriter) writeTags() {
+	var names []string
+	for name := range rw.tags {
+		names = append(names, name)
+	}
+
+	rank := func(name string) int {
+		for i, ordered := range tagOrder {
+			if name == ordered {
+				return i
+			}
+		}
+
+		return len(tagOrder)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if rank(names[i]) != rank(names[j]) {
+			return rank(names[i]) < rank(names[j])
+		}
+
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		rw.printf("[%v %q]\n", name, rw.tags[name])
+	}
+
+	for _, tag := range rw.rules.ruleTags() {
+		rw.printf("[%v %q]\n", tag[0], tag[1])
+	}
+}
+
+func (rw *RecordWriter) flushDeal() {
+	if len(rw.deal) > 0 {
+		rw.printf("Deal %v %v\n", rw.seat+1, joinCards(rw.deal))
+		rw.deal = nil
+	}
+}
+
+func (rw *RecordWriter) printf(format string, args ...interface{}) {
+	if rw.err == nil {
+		_, rw.err = fmt.Fprintf(rw.w, format, args...)
+	}
+}
+
+func joinCards(cards []Card) string {
+	text := make([]string, len(cards))
+	for i, card := range cards {
+		text[i] = card.String()
+	}
+
+	return strings.Join(text, " ")
+}
+
+// Write writes the record out again in the game record format.
+func (record *Record) Write(w io.Writer) error {
+	rw := &RecordWriter{w: w, tags: record.Tags, rules: record.Rules, started: true}
+	rw.writeTags()
+	for _, game := range record.Games {
+		rw.startGame(game.Seed)
+		for _, event := range game.Events {
+			rw.Observe(event)
+		}
+
+		rw.flushDeal()
+		rw.inGame = false
+	}
+
+	return rw.err
+}
+
+// ParseRecord reads a game record. A record with rules tags is played under
+// just the rules they give, with the values left out as 0; one without any
+// under ClassicRules.
+func ParseRecord(r io.Reader) (*Record, error) {
+	record := &Record{Tags: make(map[string]string), Rules: ClassicRules}
+	rulesSet := false
+	parser := recordParser{record: record}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+
+		line = strings.TrimSpace(line)
+		var err error
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "["):
+			var name, value string
+			name, value, err = parseTag(line)
+			if err == nil && isRulesTag(name) {
+				if !rulesSet {
+					record.Rules, rulesSet = Rules{}, true
+				}
+
+				err = record.Rules.setTag(name, value)
+			} else if err == nil {
+				record.Tags[name] = value
+			}
+		default:
+			err = parser.parseLine(line)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", n, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// parseTag splits a line such as [Date "2024.03.01"] into its name and value.
+func parseTag(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("tag %v is not closed", line)
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(line[1:len(line)-1]), " ", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("tag %v has no value", line)
+	}
+
+	value, err := strconv.Unquote(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return "", "", fmt.Errorf("tag %v: %v", line, err)
+	}
+
+	return parts[0], value, nil
+}
+
+// recordParser turns the lines of the games in a record into events.
+type recordParser struct {
+	record *Record
+	trump  string
+	trick  *Trick
+}
+
+func (p *recordParser) game() (*GameRecord, error) {
+	if len(p.record.Games) == 0 {
+		return nil, errors.New("an event comes before the first game")
+	}
+
+	return &p.record.Games[len(p.record.Games)-1], nil
+}
+
+func (p *recordParser) parseLine(line string) error {
+	fields := strings.Fields(line)
+	if fields[0] == "Game" {
+		if len(fields) != 4 || fields[2] != "seed" {
+			return fmt.Errorf("%q should read Game <number> seed <seed>", line)
+		}
+
+		seed, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		p.record.Games = append(p.record.Games, GameRecord{Seed: seed})
+		p.trick = nil
+		return nil
+	}
+
+	game, err := p.game()
+	if err != nil {
+		return err
+	}
+
+	events, err := p.parseEvents(fields[0], strings.TrimSpace(line[len(fields[0]):]))
+	if err != nil {
+		return fmt.Errorf("%v: %v", fields[0], err)
+	}
+
+	game.Events = append(game.Events, events...)
+	return nil
+}
+
+// parseEvents parses the rest of a line starting with keyword.
+func (p *recordParser) parseEvents(keyword, rest string) ([]Event, error) {
+	switch keyword {
+	case "Deal":
+		seat, cards, err := parseSeatAndCards(rest)
+		var events []Event
+		for _, card := range cards {
+			events = append(events, CardDealt{seat, card})
+		}
+
+		return events, err
+	case "Trump":
+		card, err := ParseCard(rest)
+		p.trump = card.suit
+		return []Event{TrumpTurned{card}}, err
+	case "Play":
+		seat, cards, err := parseSeatAndCards(rest)
+		if err != nil || len(cards) != 1 {
+			return nil, fmt.Errorf("%q should be a seat and a card", rest)
+		}
+
+		if p.trick == nil || p.trick.Complete() {
+			p.trick = newTrick(seat, 2, p.trump, p.record.Rules.points())
+		}
+
+		p.trick.Play(cards[0])
+		return []Event{CardPlayed{seat, cards[0]}}, nil
+	case "Trick":
+		n, err := parseInts(rest, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.trick == nil || !p.trick.Complete() {
+			return nil, errors.New("the trick has not been played")
+		}
+
+		return []Event{TrickWon{n[0] - 1, p.trick, n[1]}}, nil
+	case "Meld", "Exchange":
+		seat, item, err := parseMeld(rest)
+		return []Event{MeldDeclared{seat, item, keyword == "Exchange"}}, err
+	case "Score":
+		n, err := parseInts(rest, 5)
+		if err != nil {
+			return nil, err
+		}
+
+		return []Event{HandScored{HandScore{n[0], n[1], n[2], n[3], n[4]}}}, nil
+	case "Over":
+		return parseOver(rest)
+	}
+
+	return nil, errors.New("unknown event")
+}
+
+// parseSeatAndCards parses a seat, 1 or 2, followed by cards.
+func parseSeatAndCards(text string) (int, []Card, error) {
+	fields := strings.SplitN(text, " ", 2)
+	if len(fields) != 2 {
+		return 0, nil, fmt.Errorf("%q should be a seat and cards", text)
+	}
+
+	seat, err := parseSeat(fields[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	cards, err := ParseCards(fields[1])
+	return seat, cards, err
+}
+
+func parseSeat(text string) (int, error) {
+	if text != "1" && text != "2" {
+		return 0, fmt.Errorf("%q is not a seat: playerOne is 1 and playerTwo 2", text)
+	}
+
+	return int(text[0] - '1'), nil
+}
+
+func parseInts(text string, count int) ([]int, error) {
+	fields := strings.Fields(text)
+	if len(fields) != count {
+		return nil, fmt.Errorf("%q should be %v numbers", text, count)
+	}
+
+	n := make([]int, count)
+	for i, field := range fields {
+		var err error
+		if n[i], err = strconv.Atoi(field); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// parseMeld parses a seat, the quoted name of a meld, its points and its cards.
+func parseMeld(text string) (int, MeldItem, error) {
+	var item MeldItem
+	fields := strings.SplitN(text, " ", 2)
+	if len(fields) != 2 {
+		return 0, item, fmt.Errorf("%q should be a seat, a name, points and cards", text)
+	}
+
+	seat, err := parseSeat(fields[0])
+	if err != nil {
+		return 0, item, err
+	}
+
+	quoted, err := strconv.QuotedPrefix(fields[1])
+	if err != nil {
+		return 0, item, fmt.Errorf("the name of the meld must be quoted: %v", err)
+	}
+
+	item.Name, _ = strconv.Unquote(quoted)
+	rest := strings.SplitN(strings.TrimSpace(fields[1][len(quoted):]), " ", 2)
+	if len(rest) != 2 {
+		return 0, item, fmt.Errorf("%q should be points and cards", fields[1][len(quoted):])
+	}
+
+	if item.Points, err = strconv.Atoi(rest[0]); err != nil {
+		return 0, item, err
+	}
+
+	item.Cards, err = ParseCards(rest[1])
+	return seat, item, err
+}
+
+// parseOver parses the final scores and the result of the match.
+func parseOver(text string) ([]Event, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 || len(fields) > 4 {
+		return nil, fmt.Errorf("%q should be both scores and the result", text)
+	}
+
+	n, err := parseInts(strings.Join(fields[:2], " "), 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields[2] != "1-0" && fields[2] != "0-1" {
+		return nil, fmt.Errorf("%q is not a result: 1-0 or 0-1", fields[2])
+	}
+
+	over := GameOver{Outcome{Over: true, PlayerOneWon: fields[2] == "1-0"}, n[0], n[1]}
+	if len(fields) == 4 {
+		switch fields[3] {
+		case "declared":
+			over.Outcome.DeclaredOut = true
+		case "wrong":
+			over.Outcome.DeclaredOut, over.Outcome.WrongDeclaration = true, true
+		default:
+			return nil, fmt.Errorf("%q should be declared or wrong", fields[3])
+		}
+	}
+
+	return []Event{over}, nil
+}
@@ -0,0 +1,175 @@
+package pinochle
+
+// Rules are the house rules a Match is played under. Start from one of the
+// presets and change what your table plays differently.
+type Rules struct {
+	Counters  CounterPoints
+	Melds     MeldPoints
+	LastTrick int // bonus for taking the last trick
+	PlayingTo int // score that wins the match
+	// TargetRaise is how far PlayingTo rises when both players reach it in the
+	// same game. When it is 0 the higher score wins instead.
+	TargetRaise int
+	Dix         DixRule
+	Bidding     BidRules // for an AuctionMatch
+}
+
+// CounterPoints is what each card is worth when taken in a trick.
+type CounterPoints struct {
+	Ace, Ten, King, Queen, Jack int
+}
+
+// MeldPoints is what each meld is worth.
+type MeldPoints struct {
+	Flush, DoubleRun, RoyalMarriage, Marriage, Dix int
+
+	Aces, Kings, Queens, Jacks                         int
+	DoubleAces, DoubleKings, DoubleQueens, DoubleJacks int
+
+	Pinochle, DoublePinochle int
+
+	// Only a double deck holds enough cards for these.
+	TripleRun, QuadrupleRun                                        int
+	TripleAces, TripleKings, TripleQueens, TripleJacks             int
+	QuadrupleAces, QuadrupleKings, QuadrupleQueens, QuadrupleJacks int
+	TriplePinochle, QuadruplePinochle                              int
+}
+
+// DixRule is how the nine of trump is handled.
+type DixRule int
+
+const (
+	// DixExchange scores the dix as a meld, and lets a player who melds it
+	// exchange it for the turned-up trump card.
+	DixExchange DixRule = iota
+	// DixNoExchange scores the dix as a meld but never exchanges it.
+	DixNoExchange
+	// DixDealerScores is DixExchange, and the dealer also scores the dix when
+	// it is the card turned up for trump.
+	DixDealerScores
+)
+
+// ClassicRules is the two-handed game to 1000 with the standard point values.
+var ClassicRules = Rules{
+	Counters:  CounterPoints{Ace: 11, Ten: 10, King: 4, Queen: 3, Jack: 2},
+	LastTrick: 10,
+	Melds: MeldPoints{
+		Flush: 150, DoubleRun: 1500, RoyalMarriage: 40, Marriage: 20, Dix: 10,
+		Aces: 100, Kings: 80, Queens: 60, Jacks: 40,
+		DoubleAces: 1000, DoubleKings: 800, DoubleQueens: 600, DoubleJacks: 400,
+		Pinochle: 40, DoublePinochle: 300,
+	},
+	PlayingTo:   1000,
+	TargetRaise: 250,
+	Bidding:     BidRules{Minimum: 300, Increment: 10, StuckDealer: true},
+}
+
+// CountersRules is ClassicRules with the simplified count: aces, tens and kings
+// are worth 10 each, queens and jacks nothing.
+var CountersRules = Rules{
+	Counters:    CounterPoints{Ace: 10, Ten: 10, King: 10},
+	LastTrick:   10,
+	Melds:       ClassicRules.Melds,
+	PlayingTo:   1000,
+	TargetRaise: 250,
+	Bidding:     BidRules{Minimum: 300, Increment: 10, StuckDealer: true},
+}
+
+// PointCountRules is the modern scale, with every value divided by ten: aces,
+// tens and kings are worth 1 each and the match is played to 100.
+var PointCountRules = Rules{
+	Counters:  CounterPoints{Ace: 1, Ten: 1, King: 1},
+	LastTrick: 1,
+	Melds: MeldPoints{
+		Flush: 15, DoubleRun: 150, RoyalMarriage: 4, Marriage: 2, Dix: 1,
+		Aces: 10, Kings: 8, Queens: 6, Jacks: 4,
+		DoubleAces: 100, DoubleKings: 80, DoubleQueens: 60, DoubleJacks: 40,
+		Pinochle: 4, DoublePinochle: 30,
+	},
+	PlayingTo:   100,
+	TargetRaise: 25,
+	Bidding:     BidRules{Minimum: 30, Increment: 1, StuckDealer: true},
+}
+
+// FifteenHundredRules is the longer game to 1500, with the flush at 250 and
+// the double pinochle at 500. The dealer scores a dix turned up for trump.
+var FifteenHundredRules = Rules{
+	Counters:  ClassicRules.Counters,
+	LastTrick: 10,
+	Melds: MeldPoints{
+		Flush: 250, DoubleRun: 1500, RoyalMarriage: 40, Marriage: 20, Dix: 10,
+		Aces: 100, Kings: 80, Queens: 60, Jacks: 40,
+		DoubleAces: 1000, DoubleKings: 800, DoubleQueens: 600, DoubleJacks: 400,
+		Pinochle: 40, DoublePinochle: 500,
+	},
+	PlayingTo:   1500,
+	TargetRaise: 250,
+	Dix:         DixDealerScores,
+	Bidding:     BidRules{Minimum: 300, Increment: 10, StuckDealer: true},
+}
+
+// DoubleDeckRules is the partnership game with the 80-card double deck: aces,
+// tens and kings are worth 10 each, and the match is played to 5000.
+var DoubleDeckRules = Rules{
+	Counters:  CounterPoints{Ace: 10, Ten: 10, King: 10},
+	LastTrick: 20,
+	Melds: MeldPoints{
+		Flush: 150, DoubleRun: 1500, TripleRun: 2250, QuadrupleRun: 3000,
+		RoyalMarriage: 40, Marriage: 20,
+		Aces: 100, Kings: 80, Queens: 60, Jacks: 40,
+		DoubleAces: 1000, DoubleKings: 800, DoubleQueens: 600, DoubleJacks: 400,
+		TripleAces: 1500, TripleKings: 1200, TripleQueens: 900, TripleJacks: 600,
+		QuadrupleAces: 2000, QuadrupleKings: 1600, QuadrupleQueens: 1200, QuadrupleJacks: 800,
+		Pinochle: 40, DoublePinochle: 300, TriplePinochle: 600, QuadruplePinochle: 900,
+	},
+	PlayingTo:   5000,
+	TargetRaise: 1000,
+	Bidding:     BidRules{Minimum: 500, Increment: 10, StuckDealer: true},
+}
+
+// points converts the rules to the internal scoring table.
+func (rules Rules) points() points {
+	return points{
+		ace:   rules.Counters.Ace,
+		ten:   rules.Counters.Ten,
+		king:  rules.Counters.King,
+		queen: rules.Counters.Queen,
+		jack:  rules.Counters.Jack,
+
+		lastTrick: rules.LastTrick,
+
+		classA: classA{
+			flush:         rules.Melds.Flush,
+			doubleRun:     rules.Melds.DoubleRun,
+			tripleRun:     rules.Melds.TripleRun,
+			quadrupleRun:  rules.Melds.QuadrupleRun,
+			royalMarriage: rules.Melds.RoyalMarriage,
+			marriage:      rules.Melds.Marriage,
+			dix:           rules.Melds.Dix,
+		},
+		classB: classB{
+			hundredAces:       rules.Melds.Aces,
+			eightyKings:       rules.Melds.Kings,
+			sixtyQueens:       rules.Melds.Queens,
+			fortyJacks:        rules.Melds.Jacks,
+			thousandAces:      rules.Melds.DoubleAces,
+			eightHundredKings: rules.Melds.DoubleKings,
+			sixHundredQueens:  rules.Melds.DoubleQueens,
+			fourHundredJacks:  rules.Melds.DoubleJacks,
+			tripleAces:        rules.Melds.TripleAces,
+			tripleKings:       rules.Melds.TripleKings,
+			tripleQueens:      rules.Melds.TripleQueens,
+			tripleJacks:       rules.Melds.TripleJacks,
+			quadrupleAces:     rules.Melds.QuadrupleAces,
+			quadrupleKings:    rules.Melds.QuadrupleKings,
+			quadrupleQueens:   rules.Melds.QuadrupleQueens,
+			quadrupleJacks:    rules.Melds.QuadrupleJacks,
+		},
+		classC: classC{
+			pinochle:          rules.Melds.Pinochle,
+			doublePinochle:    rules.Melds.DoublePinochle,
+			triplePinochle:    rules.Melds.TriplePinochle,
+			quadruplePinochle: rules.Melds.QuadruplePinochle,
+		},
+	}
+}
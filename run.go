@@ -0,0 +1,233 @@
+package pinochle
+
+import "fmt"
+
+// Result summarizes a Match played to completion by Run.
+type Result struct {
+	PlayerOneWon   bool
+	PlayerOneScore int
+	PlayerTwoScore int
+	Games          int
+}
+
+// Run plays games until MatchOver, asking each Player for its plays and melds.
+// shuffle is passed along to NewGame.
+func (match *Match) Run(shuffle bool) (Result, error) {
+	var result Result
+	for !match.MatchOver() {
+		if err := match.playGame(shuffle); err != nil {
+			return result, err
+		}
+
+		result.Games++
+	}
+
+	result.PlayerOneScore = match.playerOne.score()
+	result.PlayerTwoScore = match.playerTwo.score()
+	result.PlayerOneWon = match.PlayerOneWonMatch()
+	return result, nil
+}
+
+// playGame deals a new game, plays out the trick phase with its melds and draws,
+// and then the playoff.
+func (match *Match) playGame(shuffle bool) error {
+	if err := match.NewGame(shuffle); err != nil {
+		return err
+	}
+
+	for trickPhase, lastCard := match.TrickPhase(); trickPhase; trickPhase, lastCard = match.TrickPhase() {
+		if err := match.playTrick(); err != nil {
+			return err
+		}
+
+		if err := match.runMeld(); err != nil {
+			return err
+		}
+
+		if err := match.drawAfterTrick(lastCard); err != nil {
+			return err
+		}
+	}
+
+	for match.Playoff() {
+		if err := match.playTrick(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (match *Match) playTrick() error {
+	leader := 1
+	if match.playerOneLed {
+		leader = 0
+	}
+
+	for _, idx := range []int{leader, 1 - leader} {
+		if err := match.playCard(idx, DummyCard); err != nil {
+			return err
+		}
+	}
+
+	if err := match.DecideTrickWinner(); err != nil {
+		return err
+	}
+
+	return match.AssignTrickPoints()
+}
+
+// runMeld gives the winner of the last trick its chance to meld.
+func (match *Match) runMeld() error {
+	idx := 1
+	if match.playerOneWonTrick {
+		idx = 0
+	}
+
+	meld, err := match.seatAt(idx).player.Meld(match.table(idx))
+	if err != nil {
+		return err
+	}
+
+	// A dix is better exchanged for the turned-up trump card than just melded.
+	if compareCardSlices(meld, match.meldSlices.dix) {
+		exchange := match.PlayerTwoExchangeDix
+		if idx == 0 {
+			exchange = match.PlayerOneExchangeDix
+		}
+
+		if exchange() == nil {
+			match.DoneMelding()
+			return nil
+		}
+	}
+
+	if meld != nil {
+		var melded bool
+		if idx == 0 {
+			melded = match.PlayerOneMeld(meld)
+		} else {
+			melded = match.PlayerTwoMeld(meld)
+		}
+
+		if !melded {
+			return fmt.Errorf("meld %v was rejected", meld)
+		}
+	}
+
+	match.DoneMelding()
+	return nil
+}
+
+// drawAfterTrick has the winner of the last trick draw first. On the last card
+// of the stack the loser takes the trump card.
+func (match *Match) drawAfterTrick(lastCard bool) error {
+	var errWinner, errLoser error
+	if match.playerOneWonTrick {
+		errWinner = match.DealToPlayerOne()
+		if lastCard {
+			errLoser = match.DealTrumpToPlayerTwo()
+		} else {
+			errLoser = match.DealToPlayerTwo()
+		}
+	} else {
+		errWinner = match.DealToPlayerTwo()
+		if lastCard {
+			errLoser = match.DealTrumpToPlayerOne()
+		} else {
+			errLoser = match.DealToPlayerOne()
+		}
+	}
+
+	if errWinner != nil {
+		return errWinner
+	}
+
+	return errLoser
+}
+
+// AuctionResult summarizes an AuctionMatch played to completion by Run.
+type AuctionResult struct {
+	Winner int   // the winning side
+	Scores []int // by side
+	Games  int
+}
+
+// Run plays hands until MatchOver, asking each Player for its plays. Players
+// that are Bidders also bid, name trump, bury and pass cards for themselves; a
+// Computer does it for the rest. shuffle is passed along to NewGame.
+func (match *AuctionMatch) Run(shuffle bool) (AuctionResult, error) {
+	var result AuctionResult
+	for !match.MatchOver() {
+		if err := match.playHand(shuffle); err != nil {
+			return result, err
+		}
+
+		result.Games++
+	}
+
+	result.Winner = match.Winner()
+	result.Scores = match.Scores()
+	return result, nil
+}
+
+// playHand deals a new hand, runs the auction and plays the hand out.
+func (match *AuctionMatch) playHand(shuffle bool) error {
+	if err := match.NewGame(shuffle); err != nil {
+		return err
+	}
+
+	for match.Bidding() {
+		player := match.Turn()
+		minimum := match.MinimumBid()
+		if bid := match.bidderFor(player).Bid(match.table(player), minimum); bid > 0 {
+			if err := match.Bid(player, bid); err != nil {
+				return err
+			}
+		} else if err := match.Pass(player); err != nil {
+			return err
+		}
+	}
+
+	// Everyone passed and the hand was thrown in.
+	if match.phase == phaseDone {
+		return nil
+	}
+
+	bidder := match.Contract().Bidder
+	b := match.bidderFor(bidder)
+	if err := match.NameTrump(b.NameTrump(match.table(bidder))); err != nil {
+		return err
+	}
+
+	if match.phase == phaseBury {
+		if err := match.Bury(b.Bury(match.table(bidder), match.widowSize)); err != nil {
+			return err
+		}
+	}
+
+	for match.phase == phasePass {
+		player := match.Turn()
+		cards := match.bidderFor(player).PassCards(match.table(player), match.passSize, player != bidder)
+		if err := match.PassCards(player, cards); err != nil {
+			return err
+		}
+	}
+
+	for match.Playing() {
+		if err := match.Play(match.Turn(), DummyCard); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bidderFor returns the Bidder that makes player's auction decisions.
+func (match *AuctionMatch) bidderFor(player int) Bidder {
+	if b, ok := match.seats[player].player.(Bidder); ok {
+		return b
+	}
+
+	return &Computer{}
+}
@@ -0,0 +1,85 @@
+package pinochle
+
+import "fmt"
+
+// seat is the Match's bookkeeping for one Player: its hand, melds and scores.
+// currentMeldScore and currentTrickScore belong to the game in progress, and
+// currentScore to the games already finished.
+type seat struct {
+	player            Player
+	hand              []Card
+	currentTrickScore int
+	currentMeldScore  int
+	currentScore      int
+	melds             [][]Card
+	meldItems         []MeldItem
+}
+
+func (s *seat) storeMeld(meld []Card) {
+	s.melds = append(s.melds, meld)
+}
+
+func (s *seat) scoreTrickPoints(points int) {
+	s.currentTrickScore += points
+}
+
+func (s *seat) scoreMeldPoints(points int) {
+	s.currentMeldScore += points
+}
+
+// mergeMeldsAndTricks adds the finished game's melds and tricks to the score.
+func (s *seat) mergeMeldsAndTricks() {
+	s.currentScore += s.currentMeldScore + s.currentTrickScore
+	s.currentMeldScore = 0
+	s.currentTrickScore = 0
+}
+
+func (s *seat) meldScore() int {
+	return s.currentMeldScore
+}
+
+func (s *seat) hasCards() bool {
+	return len(s.hand) > 0
+}
+
+func (s *seat) getMelds() [][]Card {
+	return s.melds
+}
+
+// score returns the points of every finished game.
+func (s *seat) score() int {
+	return s.currentScore
+}
+
+// gameScore returns the points of the game in progress.
+func (s *seat) gameScore() int {
+	return s.currentMeldScore + s.currentTrickScore
+}
+
+func (s *seat) pushToHand(card Card) {
+	s.hand = append(s.hand, card)
+}
+
+func (s *seat) getHand() []Card {
+	return s.hand
+}
+
+func (s *seat) handContains(card Card) (bool, int) {
+	for idx, cardInHand := range s.hand {
+		if CompareCards(card, cardInHand) {
+			return true, idx
+		}
+	}
+	return false, -1
+}
+
+// play removes card from the hand. If the hand doesn't contain the card,
+// a non-nil error will be returned.
+func (s *seat) play(card Card) (Card, error) {
+	success, idx := s.handContains(card)
+	if success {
+		s.hand = removeCard(s.hand, idx)
+		return card, nil
+	}
+	return DummyCard, fmt.Errorf("hand does not contain card %v", card)
+}
@@ -0,0 +1,346 @@
+package pinochle
+
+import (
+	"errors"
+	"math"
+)
+
+// Solution is the outcome of perfect play from a playoff position.
+type Solution struct {
+	// PlayerOnePoints and PlayerTwoPoints are the trick points, including the
+	// last trick bonus, each player secures from here on.
+	PlayerOnePoints int
+	PlayerTwoPoints int
+	// Line is an optimal sequence of cards, in the order they are played.
+	Line []Card
+}
+
+// SolvePlayoff solves the rest of the playoff by alpha-beta search. Once the
+// stock is gone both hands are known, so the result is exact.
+func (match *Match) SolvePlayoff() (Solution, error) {
+	if trickPhase, _ := match.TrickPhase(); trickPhase {
+		return Solution{}, errors.New("the stock still has cards, so the hands aren't known")
+	}
+
+	leader := 1
+	if match.playerOneLed {
+		leader = 0
+	}
+
+	led := DummyCard
+	for idx := range match.inTrick {
+		if card, answering := match.currentLead(idx); answering {
+			leader, led = 1-idx, card
+		}
+	}
+
+	s := newSolver(match.deck.trump.suit, match.pointValues)
+	pos := s.position([2][]Card{match.playerOne.getHand(), match.playerTwo.getHand()}, leader, led)
+	if pos.empty() {
+		return Solution{}, errors.New("there are no cards left to play")
+	}
+
+	value := s.search(pos, math.MinInt32, math.MaxInt32)
+	total := match.pointValues.lastTrick
+	for _, hand := range [][]Card{match.playerOne.getHand(), match.playerTwo.getHand(), {led}} {
+		for _, card := range hand {
+			total += match.pointValues.cardPoints(card)
+		}
+	}
+
+	return Solution{
+		PlayerOnePoints: value,
+		PlayerTwoPoints: total - value,
+		Line:            s.line(pos),
+	}, nil
+}
+
+// A solverPosition keeps both hands as counts per card index, which is the
+// suit index times len(faceValues) plus the face value index.
+type solverPosition struct {
+	counts [2][24]int8
+	leader int
+	led    int // card index led to the current trick, -1 when none
+}
+
+type solverKey struct {
+	hands  [2]uint64
+	leader int
+	led    int
+}
+
+const (
+	exactBound = iota
+	lowerBound
+	upperBound
+)
+
+type solverEntry struct {
+	value int
+	bound int
+}
+
+// solver searches playoff positions for the points playerOne (seat 0) takes
+// from them, with seat 0 maximizing and seat 1 minimizing.
+type solver struct {
+	trump     int
+	points    [24]int
+	lastTrick int
+	table     map[solverKey]solverEntry
+}
+
+func newSolver(trumpSuit string, values points) *solver {
+	s := &solver{lastTrick: values.lastTrick, table: make(map[solverKey]solverEntry)}
+	for i, suit := range suits {
+		if suit == trumpSuit {
+			s.trump = i
+		}
+	}
+
+	for i := range s.points {
+		s.points[i] = values.cardPoints(solverCard(i))
+	}
+
+	return s
+}
+
+func solverIndex(card Card) int {
+	idx := 0
+	for i, suit := range suits {
+		if suit == card.suit {
+			idx = i * len(faceValues)
+		}
+	}
+
+	for i, face := range faceValues {
+		if face == card.faceValue {
+			idx += i
+		}
+	}
+
+	return idx
+}
+
+func solverCard(idx int) Card {
+	return Card{faceValues[idx%len(faceValues)], suits[idx/len(faceValues)]}
+}
+
+func (s *solver) position(hands [2][]Card, leader int, led Card) solverPosition {
+	pos := solverPosition{leader: leader, led: -1}
+	for seat, hand := range hands {
+		for _, card := range hand {
+			pos.counts[seat][solverIndex(card)]++
+		}
+	}
+
+	if !CompareCards(led, DummyCard) {
+		pos.led = solverIndex(led)
+	}
+
+	return pos
+}
+
+func (pos *solverPosition) empty() bool {
+	return pos.counts[0] == [24]int8{} && pos.counts[1] == [24]int8{}
+}
+
+func (pos *solverPosition) key() solverKey {
+	key := solverKey{leader: pos.leader, led: pos.led}
+	for seat := range pos.counts {
+		for i, count := range pos.counts[seat] {
+			key.hands[seat] |= uint64(count) << (2 * uint(i))
+		}
+	}
+
+	return key
+}
+
+func (pos *solverPosition) turn() int {
+	if pos.led < 0 {
+		return pos.leader
+	}
+
+	return 1 - pos.leader
+}
+
+// moves returns the distinct legal card indexes for the seat to play. Card
+// indexes run from the ace down to the nine within each suit, so high cards,
+// which tend to cut the search off sooner, are tried first.
+func (s *solver) moves(pos *solverPosition) []int {
+	hand := &pos.counts[pos.turn()]
+	var held []int
+	for i := range hand {
+		if hand[i] > 0 {
+			held = append(held, i)
+		}
+	}
+
+	if pos.led < 0 {
+		return held
+	}
+
+	ledSuit, ledFace := pos.led/len(faceValues), pos.led%len(faceValues)
+	var follow, head, trump []int
+	for _, i := range held {
+		switch suit := i / len(faceValues); {
+		case suit == ledSuit:
+			follow = append(follow, i)
+			if i%len(faceValues) < ledFace {
+				head = append(head, i)
+			}
+		case suit == s.trump:
+			trump = append(trump, i)
+		}
+	}
+
+	switch {
+	case len(head) > 0:
+		return head
+	case len(follow) > 0:
+		return follow
+	case len(trump) > 0:
+		return trump
+	}
+
+	return held
+}
+
+// apply plays card for the seat to move and returns the points playerOne took
+// if it completed a trick, along with what's needed to undo it.
+func (s *solver) apply(pos *solverPosition, card int) (gained, leader, led int) {
+	seat := pos.turn()
+	leader, led = pos.leader, pos.led
+	pos.counts[seat][card]--
+	if pos.led < 0 {
+		pos.led = card
+		pos.leader = seat
+		return 0, leader, led
+	}
+
+	winner := pos.leader
+	ledSuit, suit := pos.led/len(faceValues), card/len(faceValues)
+	if (suit == ledSuit && card%len(faceValues) < pos.led%len(faceValues)) || (suit != ledSuit && suit == s.trump) {
+		winner = seat
+	}
+
+	points := s.points[pos.led] + s.points[card]
+	pos.led = -1
+	pos.leader = winner
+	if pos.empty() {
+		points += s.lastTrick
+	}
+
+	if winner == 0 {
+		return points, leader, led
+	}
+
+	return 0, leader, led
+}
+
+func (s *solver) undo(pos *solverPosition, card, seat, leader, led int) {
+	pos.counts[seat][card]++
+	pos.leader = leader
+	pos.led = led
+}
+
+// search returns the points playerOne takes from pos with best play on both
+// sides, within the window (alpha, beta).
+func (s *solver) search(pos solverPosition, alpha, beta int) int {
+	if pos.empty() {
+		return 0
+	}
+
+	key := pos.key()
+	if entry, ok := s.table[key]; ok {
+		switch {
+		case entry.bound == exactBound:
+			return entry.value
+		case entry.bound == lowerBound && entry.value >= beta:
+			return entry.value
+		case entry.bound == upperBound && entry.value <= alpha:
+			return entry.value
+		}
+	}
+
+	seat := pos.turn()
+	originalAlpha, originalBeta := alpha, beta
+	best := math.MinInt32
+	if seat == 1 {
+		best = math.MaxInt32
+	}
+
+	for _, card := range s.moves(&pos) {
+		gained, leader, led := s.apply(&pos, card)
+		value := gained + s.search(pos, alpha-gained, beta-gained)
+		s.undo(&pos, card, seat, leader, led)
+
+		if seat == 0 {
+			if value > best {
+				best = value
+			}
+			if best > alpha {
+				alpha = best
+			}
+		} else {
+			if value < best {
+				best = value
+			}
+			if best < beta {
+				beta = best
+			}
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	entry := solverEntry{value: best, bound: exactBound}
+	if best <= originalAlpha {
+		entry.bound = upperBound
+	} else if best >= originalBeta {
+		entry.bound = lowerBound
+	}
+	s.table[key] = entry
+
+	return best
+}
+
+// best returns an optimal card for the seat to move in pos.
+func (s *solver) best(pos solverPosition) int {
+	target := s.search(pos, math.MinInt32, math.MaxInt32)
+	seat := pos.turn()
+	moves := s.moves(&pos)
+	for _, card := range moves {
+		gained, leader, led := s.apply(&pos, card)
+		value := gained + s.search(pos, math.MinInt32, math.MaxInt32)
+		s.undo(&pos, card, seat, leader, led)
+		if value == target {
+			return card
+		}
+	}
+
+	return moves[0]
+}
+
+// line returns an optimal sequence of cards from pos to the end.
+func (s *solver) line(pos solverPosition) []Card {
+	var line []Card
+	for !pos.empty() {
+		card := s.best(pos)
+		s.apply(&pos, card)
+		line = append(line, solverCard(card))
+	}
+
+	return line
+}
+
+// solve plays the playoff perfectly. With the stock gone, every card the
+// Computer can't see is in the opponent's hand, so the one deal determinize
+// makes is the real one.
+func (c *Computer) solve(table Table) Card {
+	game := determinize(table, c.random())
+	s := newSolver(table.Trump.suit, table.values())
+	pos := s.position(game.hands, game.leader, game.led)
+	return solverCard(s.best(pos))
+}
@@ -0,0 +1,173 @@
+package pinochle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Trick is one trick: who led it, and the cards played to it in order, one per
+// seat going round the table from the leader.
+type Trick struct {
+	leader int
+	seats  int
+	plays  []Card
+	trump  string
+	values points
+}
+
+// NewTrick starts a trick led by the seat leader, at a table of seats seats,
+// with trumpSuit as trump and counters valued by rules.
+func NewTrick(leader, seats int, trumpSuit Suit, rules Rules) *Trick {
+	return newTrick(leader, seats, string(trumpSuit), rules.points())
+}
+
+func newTrick(leader, seats int, trumpSuit string, values points) *Trick {
+	return &Trick{leader: leader, seats: seats, trump: trumpSuit, values: values}
+}
+
+// Leader returns the seat that led the trick.
+func (trick *Trick) Leader() int {
+	return trick.leader
+}
+
+// LedSuit returns the suit led, or "" before the lead.
+func (trick *Trick) LedSuit() Suit {
+	if len(trick.plays) == 0 {
+		return ""
+	}
+
+	return trick.plays[0].Suit()
+}
+
+// Plays returns the cards played so far, in order from the leader's.
+func (trick *Trick) Plays() []Card {
+	return append([]Card(nil), trick.plays...)
+}
+
+// Seat returns the seat that made the play at index i of Plays.
+func (trick *Trick) Seat(i int) int {
+	return (trick.leader + i) % trick.seats
+}
+
+// Next returns the seat to play next.
+func (trick *Trick) Next() int {
+	return trick.Seat(len(trick.plays))
+}
+
+// Complete reports whether every seat has played.
+func (trick *Trick) Complete() bool {
+	return len(trick.plays) == trick.seats
+}
+
+// Play adds card as the next seat's play. It checks only that the trick isn't
+// complete; see Legal for the rules of following.
+func (trick *Trick) Play(card Card) error {
+	if trick.Complete() {
+		return errors.New("every seat has played to the trick")
+	}
+
+	trick.plays = append(trick.plays, card)
+	return nil
+}
+
+// Legal returns the cards of hand the next seat may play under the rules of
+// following: follow suit and head the trick, or else trump, and overtrump.
+func (trick *Trick) Legal(hand []Card) []Card {
+	return trickLegal(hand, trick.plays, trick.trump)
+}
+
+// Resolve returns the seat that takes the trick and the counters in it. Of two
+// identical cards the one played first wins.
+func (trick *Trick) Resolve() (int, int) {
+	if len(trick.plays) == 0 {
+		return trick.leader, 0
+	}
+
+	points := 0
+	for _, card := range trick.plays {
+		points += trick.values.cardPoints(card)
+	}
+
+	return trick.Seat(trickWinner(trick.plays, trick.trump)), points
+}
+
+// trickWinner returns the index in trick of the card that takes it: the highest
+// trump, or failing that the highest card of the suit led. Of two identical
+// cards the one played first wins.
+func trickWinner(trick []Card, trumpSuit string) int {
+	winner := 0
+	for i, card := range trick[1:] {
+		if beats(card, trick[winner], trumpSuit) {
+			winner = i + 1
+		}
+	}
+
+	return winner
+}
+
+// trickViolation returns an error if card may not be played from hand to trick,
+// the cards played so far. A player must follow suit, and head the trick if the
+// suit led is still winning it. A player who can't follow must trump, and beat
+// any trump already played. Only a player who can do neither may discard.
+func trickViolation(hand, trick []Card, card Card, trumpSuit string) error {
+	if len(trick) == 0 {
+		return nil
+	}
+
+	led := trick[0]
+	winning := trick[trickWinner(trick, trumpSuit)]
+	var canFollow, canHead, canTrump, canOvertrump bool
+	for _, c := range hand {
+		if c.suit == led.suit {
+			canFollow = true
+			if beats(c, winning, trumpSuit) {
+				canHead = true
+			}
+		}
+
+		if c.suit == trumpSuit {
+			canTrump = true
+			if beats(c, winning, trumpSuit) {
+				canOvertrump = true
+			}
+		}
+	}
+
+	if card.suit == led.suit {
+		if canHead && !beats(card, winning, trumpSuit) {
+			return fmt.Errorf("%v must head the trick: a card beating %v is held", card, winning)
+		}
+
+		return nil
+	}
+
+	if canFollow {
+		return fmt.Errorf("%v must follow suit: %v was led", card, led)
+	}
+
+	if card.suit == trumpSuit {
+		if canOvertrump && !beats(card, winning, trumpSuit) {
+			return fmt.Errorf("%v must beat %v: a higher trump is held", card, winning)
+		}
+
+		return nil
+	}
+
+	if canTrump {
+		return fmt.Errorf("%v must trump: no %v is held but trump %v is", card, led.suit, trumpSuit)
+	}
+
+	return nil
+}
+
+// trickLegal returns the cards of hand that may be played to trick.
+func trickLegal(hand, trick []Card, trumpSuit string) []Card {
+	var legal []Card
+	for _, card := range hand {
+		if trickViolation(hand, trick, card, trumpSuit) == nil {
+			legal = append(legal, card)
+		}
+	}
+
+	return legal
+}